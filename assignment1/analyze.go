@@ -0,0 +1,80 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+)
+
+func analyze(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var asJSON bool
+	fs := flag.NewFlagSet("rle analyze", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.BoolVar(&asJSON, "json", false, "print the report as JSON instead of a table")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage // already reported by fs
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(stderr, "%s: too many arguments\n", fs.Name())
+		fs.Usage()
+		return errUsage
+	}
+
+	r := stdin
+	if input := fs.Arg(0); input != "" && input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	report := stringManipulator.Analyze(string(b))
+	if asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	}
+	return printReport(stdout, report)
+}
+
+// printReport writes r as aligned tables
+func printReport(w io.Writer, r stringManipulator.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(tw, "length\t%d bytes\n", r.Length)
+	fmt.Fprintf(tw, "chars\t%d\n", r.Chars)
+	fmt.Fprintf(tw, "digits\t%d\n", r.Digits)
+	fmt.Fprintf(tw, "runs\t%d\n", r.Runs)
+	fmt.Fprintf(tw, "expansion risk\t%v\n", r.Risk)
+
+	fmt.Fprintf(tw, "\nrun length\tcount\n")
+	for _, b := range r.Histogram {
+		fmt.Fprintf(tw, "%d\t%d\n", b.Length, b.Count)
+	}
+
+	fmt.Fprintf(tw, "\nlongest runs\tlength\toffset\n")
+	for _, run := range r.LongestRuns {
+		fmt.Fprintf(tw, "%s\t%d\t%d\n", strconv.QuoteToGraphic(run.Char), run.Length, run.Offset)
+	}
+
+	fmt.Fprintf(tw, "\nmode\tsize\tratio\tlossless\n")
+	for _, e := range r.Estimates {
+		fmt.Fprintf(tw, "%s\t%d\t%.3f\t%t\n", e.Mode, e.Size, e.Ratio, e.Lossless)
+	}
+	return tw.Flush()
+}
@@ -1,7 +1,605 @@
+// Command rle compresses and unpacks files with the stringManipulator package.
+//
+// Usage:
+//
+//	rle compress [flags] [input]
+//	rle unpack [flags] [input]
+//	rle analyze [-json] [input]
+//	rle pbm [-d] [-plain] [input]
+//
+// Input is read from the named file, or from stdin when it is omitted or "-".
+// Output is written to the file named by -o, or to stdout.
 package main
 
-import "fmt"
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+)
+
+const usage = `usage: rle <command> [flags] [input]
+
+commands:
+  compress   compress input
+  unpack     unpack input
+  analyze    report how well input compresses in each mode
+  pbm        run-length encode the scanlines of a PBM image, or decode them with -d
+
+Run "rle <command> -h" for the flags of a command.
+`
+
+// errUsage is returned when the command line is invalid, after the problem has been reported
+var errUsage = errors.New("usage")
+
+// command runs a subcommand with its arguments
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"compress": compress,
+	"unpack":   unpack,
+	"analyze":  analyze,
+	"pbm":      pbmCommand,
+}
 
 func main() {
-	fmt.Println("Main is here if you want it.")
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 1 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "rle: unknown command %q\n%s", args[0], usage)
+		return 2
+	}
+
+	switch err := cmd(args[1:], stdin, stdout, stderr); {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	default:
+		fmt.Fprintf(stderr, "rle %s: %v\n", args[0], err)
+		return 1
+	}
+}
+
+// codecFlags holds the flags shared by compress and unpack
+type codecFlags struct {
+	output string
+	format string
+	count  string
+	unit   string
+	escape string
+	codec  string
+	maxRun int
+	seal   bool
+	stats  bool
+
+	normalize string
+	fold      bool
+
+	parallel  bool
+	chunkSize int
+	workers   int
+}
+
+// newFlagSet returns a flag set for a subcommand with the shared flags registered
+func newFlagSet(name string, stderr io.Writer, f *codecFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("rle "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&f.output, "o", "", "write output to `file` instead of stdout")
+	if name == "unpack" {
+		fs.StringVar(&f.format, "format", "auto", "encoding: auto to detect legacy, escaped, tokens, sealed, chunked or plain input, or legacy, escaped, packbits or tokens")
+	} else {
+		fs.StringVar(&f.format, "format", "legacy", "encoding: legacy, escaped, packbits or tokens")
+	}
+	fs.StringVar(&f.codec, "codec", "", "use a registered codec by `name` instead of -format, or auto to choose the smallest output")
+	fs.StringVar(&f.count, "count", "decimal", "count encoding: decimal, varint or delimited")
+	fs.StringVar(&f.unit, "unit", "rune", "unit of repetition: rune, grapheme or entity")
+	fs.StringVar(&f.escape, "escape", "", "escape `rune` for the escaped format (default \\, or ~ with -unit entity)")
+	fs.StringVar(&f.normalize, "normalize", "none", "Unicode normalization applied before compressing with -format escaped: none, nfc or nfkc")
+	fs.BoolVar(&f.fold, "fold", false, "case-fold before compressing with -format escaped")
+	fs.IntVar(&f.maxRun, "max-run", 0, "longest run written with a single count, 0 for the encoding's maximum")
+	fs.BoolVar(&f.seal, "seal", false, "wrap the output of -codec (default rle) in a container with a checksum; unpack reads such containers")
+	fs.BoolVar(&f.parallel, "parallel", false, "encode independent chunks of the input with -codec (default rle) on several goroutines; unpack reads such chunked output")
+	fs.IntVar(&f.chunkSize, "chunk-size", stringManipulator.DefaultChunkSize, "input `bytes` per chunk with -parallel")
+	fs.IntVar(&f.workers, "workers", 0, "goroutines used with -parallel, 0 for GOMAXPROCS")
+	fs.BoolVar(&f.stats, "stats", false, "report sizes and the compression ratio on stderr")
+	return fs
+}
+
+// parse parses args and returns the input file name
+func (f *codecFlags) parse(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return "", err
+		}
+		return "", errUsage // already reported by fs
+	}
+	switch fs.NArg() {
+	case 0:
+		return "-", nil
+	case 1:
+		return fs.Arg(0), nil
+	}
+	fmt.Fprintf(fs.Output(), "%s: too many arguments\n", fs.Name())
+	fs.Usage()
+	return "", errUsage
+}
+
+// options converts the flags to stringManipulator options
+func (f *codecFlags) options() (stringManipulator.Options, error) {
+	var opts stringManipulator.Options
+	switch f.format {
+	case "legacy", "packbits", "tokens", "auto":
+		opts.Format = stringManipulator.FormatLegacy
+	case "escaped":
+		opts.Format = stringManipulator.FormatEscaped
+	default:
+		return opts, fmt.Errorf("unknown format %q", f.format)
+	}
+	switch f.count {
+	case "decimal":
+		opts.CountEncoding = stringManipulator.Decimal
+	case "varint":
+		opts.CountEncoding = stringManipulator.Varint
+	case "delimited":
+		opts.CountEncoding = stringManipulator.Delimited
+	default:
+		return opts, fmt.Errorf("unknown count encoding %q", f.count)
+	}
+	switch f.unit {
+	case "rune":
+		opts.Unit = stringManipulator.UnitRune
+	case "grapheme":
+		opts.Unit = stringManipulator.UnitGrapheme
+	case "entity":
+		opts.Unit = stringManipulator.UnitEntity
+	default:
+		return opts, fmt.Errorf("unknown unit %q", f.unit)
+	}
+	switch f.normalize {
+	case "none":
+	case "nfc":
+		opts.Normalize = stringManipulator.NFC
+	case "nfkc":
+		opts.Normalize = stringManipulator.NFKC
+	default:
+		return opts, fmt.Errorf("unknown normalization %q", f.normalize)
+	}
+	opts.CaseFold = f.fold
+	if f.escape != "" {
+		if utf8.RuneCountInString(f.escape) != 1 {
+			return opts, fmt.Errorf("escape must be a single character, got %q", f.escape)
+		}
+		opts.Escape, _ = utf8.DecodeRuneInString(f.escape)
+	}
+	opts.MaxRun = f.maxRun
+	return opts, nil
+}
+
+// lookupCodec returns the codec named by the -codec flag
+func (f *codecFlags) lookupCodec() (stringManipulator.Codec, error) {
+	c, ok := stringManipulator.CodecByName(f.codec)
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q", f.codec)
+	}
+	return c, nil
+}
+
+// encode encodes b with the codec named by the -codec flag, choosing the smallest output for auto and rle when unset
+func (f *codecFlags) encode(b []byte) (stringManipulator.Codec, []byte, error) {
+	switch f.codec {
+	case "auto":
+		return stringManipulator.Best(b)
+	case "":
+		f.codec = "rle"
+	}
+	c, err := f.lookupCodec()
+	if err != nil {
+		return nil, nil, err
+	}
+	out, err := c.Encode(b)
+	return c, out, err
+}
+
+// parallelOptions converts the flags to options for CompressParallel and UnpackParallel
+func (f *codecFlags) parallelOptions() (stringManipulator.ParallelOptions, error) {
+	if f.seal {
+		return stringManipulator.ParallelOptions{}, errors.New("-parallel cannot be combined with -seal")
+	}
+	if f.chunkSize < 1 {
+		return stringManipulator.ParallelOptions{}, fmt.Errorf("chunk size must be positive, got %d", f.chunkSize)
+	}
+	opts := stringManipulator.ParallelOptions{ChunkSize: f.chunkSize, Workers: f.workers}
+	switch f.codec {
+	case "auto":
+		return opts, errors.New("-codec auto cannot be combined with -parallel")
+	case "":
+	default:
+		c, err := f.lookupCodec()
+		if err != nil {
+			return opts, err
+		}
+		opts.Codec = c
+	}
+	return opts, nil
+}
+
+// formatFlags are the flags that describe -format, which codecs do not read
+var formatFlags = []string{"format", "count", "unit", "escape", "normalize", "fold", "max-run", "strict", "max-output"}
+
+// checkCodec returns an error when -seal, -parallel or -codec is given with one of formatFlags, which would have no effect
+func (f *codecFlags) checkCodec(fs *flag.FlagSet) error {
+	var mode string
+	switch {
+	case f.seal:
+		mode = "-seal"
+	case f.parallel:
+		mode = "-parallel"
+	case f.codec != "":
+		mode = "-codec"
+	default:
+		return nil
+	}
+	for _, name := range formatFlags {
+		if flagSet(fs, name) {
+			return fmt.Errorf("%s cannot be combined with -%s", mode, name)
+		}
+	}
+	return nil
+}
+
+// streaming reports whether the flags select the default format, which is processed in bounded memory
+func (f *codecFlags) streaming(opts stringManipulator.Options) bool {
+	return f.codec == "" && !f.seal && !f.parallel && f.format == "legacy" && opts == stringManipulator.Options{}
+}
+
+// splitter returns the Splitter described by the -split flag
+func splitter(spec string) (stringManipulator.Splitter, error) {
+	switch {
+	case spec == "whitespace":
+		return stringManipulator.Whitespace, nil
+	case strings.HasPrefix(spec, "delim:") && len(spec) > len("delim:"):
+		return stringManipulator.Delimiter(strings.TrimPrefix(spec, "delim:")), nil
+	case strings.HasPrefix(spec, "regexp:"):
+		re, err := regexp.Compile(strings.TrimPrefix(spec, "regexp:"))
+		if err != nil {
+			return nil, err
+		}
+		return stringManipulator.Regexp(re), nil
+	}
+	return nil, fmt.Errorf("unknown splitter %q", spec)
+}
+
+func compress(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var f codecFlags
+	var split, armorName string
+	fs := newFlagSet("compress", stderr, &f)
+	fs.StringVar(&split, "split", "whitespace", "token separators for -format tokens: whitespace, delim:`sep` or regexp:`expr`")
+	fs.StringVar(&armorName, "armor", "none", "text-safe encoding of the output: none, base64url, ascii85 or quoted")
+	input, err := f.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := f.checkCodec(fs); err != nil {
+		return err
+	}
+	opts, err := f.options()
+	if err != nil {
+		return err
+	}
+	a, err := armor(armorName)
+	if err != nil {
+		return err
+	}
+	if f.format == "auto" {
+		return errors.New("-format auto is only supported by unpack")
+	}
+
+	return process(input, f.output, stdin, stdout, stderr, f.stats, armored(a, func(r io.Reader, w io.Writer) error {
+		if f.streaming(opts) {
+			enc := stringManipulator.NewEncoder(w)
+			if _, err := io.Copy(enc, r); err != nil {
+				return err
+			}
+			return enc.Close()
+		}
+
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if f.parallel {
+			popts, err := f.parallelOptions()
+			if err != nil {
+				return err
+			}
+			out, err := stringManipulator.CompressParallel(b, popts)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(out)
+			return err
+		}
+		if f.codec != "" || f.seal {
+			c, out, err := f.encode(b)
+			if err != nil {
+				return err
+			}
+			if f.codec == "auto" {
+				fmt.Fprintf(stderr, "rle compress: chose codec %s\n", c.Name())
+			}
+			if f.seal {
+				out = stringManipulator.SealPayload(c, len(b), out)
+			}
+			_, err = w.Write(out)
+			return err
+		}
+		switch f.format {
+		case "packbits":
+			_, err = w.Write(stringManipulator.CompressBytes(b))
+			return err
+		case "tokens":
+			splitFunc, err := splitter(split)
+			if err != nil {
+				return err
+			}
+			s, err := stringManipulator.CompressTokens(string(b), splitFunc)
+			if err != nil {
+				return err
+			}
+			_, err = io.WriteString(w, s)
+			return err
+		}
+		s, err := stringManipulator.CompressWith(string(b), opts)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, s)
+		return err
+	}))
+}
+
+func unpack(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var f codecFlags
+	var strict bool
+	var armorName string
+	var maxOutput int
+	fs := newFlagSet("unpack", stderr, &f)
+	fs.BoolVar(&strict, "strict", false, "reject legacy input with digits that are not counts; selects -format legacy unless -format is given")
+	fs.IntVar(&maxOutput, "max-output", stringManipulator.DefaultMaxOutput, "fail instead of unpacking more than `bytes` bytes of escaped, delimited or varint input")
+	fs.StringVar(&armorName, "armor", "auto", "armor of the input: auto detects base64url, ascii85 and quoted armor from its prefix, none reads the input as it is")
+	input, err := f.parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := f.checkCodec(fs); err != nil {
+		return err
+	}
+	opts, err := f.options()
+	if err != nil {
+		return err
+	}
+	if armorName != "auto" && armorName != "none" {
+		return fmt.Errorf("unknown armor %q for unpack, want none or auto", armorName)
+	}
+	if f.format == "auto" && (strict || opts != stringManipulator.Options{}) {
+		// The options describe the legacy or escaped format, which auto detection does not read
+		if flagSet(fs, "format") {
+			return errors.New("-format auto cannot be combined with -strict, -count, -unit, -escape, -normalize, -fold or -max-run")
+		}
+		f.format = "legacy"
+	}
+
+	return process(input, f.output, stdin, stdout, stderr, f.stats, dearmored(armorName == "auto", func(r io.Reader, w io.Writer) error {
+		if f.streaming(opts) && !strict {
+			_, err := io.Copy(w, stringManipulator.NewDecoder(r))
+			return err
+		}
+
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		var out []byte
+		switch {
+		case f.parallel:
+			var popts stringManipulator.ParallelOptions
+			if popts, err = f.parallelOptions(); err == nil {
+				out, err = stringManipulator.UnpackParallel(b, popts)
+			}
+		case f.seal:
+			out, err = stringManipulator.Open(b)
+		case f.codec == "auto":
+			return errors.New("-codec auto is only supported by compress")
+		case f.codec != "":
+			var c stringManipulator.Codec
+			if c, err = f.lookupCodec(); err == nil {
+				out, err = c.Decode(b)
+			}
+		case f.format == "packbits":
+			out, err = stringManipulator.UnpackBytes(b)
+		case f.format == "tokens":
+			var s string
+			s, err = stringManipulator.UnpackTokens(string(b))
+			out = []byte(s)
+		case strict && f.streaming(opts):
+			var s string
+			s, err = stringManipulator.UnpackStrict(string(b))
+			out = []byte(s)
+		case f.format == "auto":
+			var kind stringManipulator.Kind
+			out, kind, err = stringManipulator.DecodeAuto(b)
+			if errors.Is(err, stringManipulator.ErrAmbiguous) {
+				return fmt.Errorf("%w; pass -format legacy to unpack it", err)
+			}
+			if err == nil && kind == stringManipulator.KindEscaped {
+				warnLossy(stderr, b)
+			}
+		default:
+			var s string
+			opts.MaxOutput = maxOutput
+			s, err = stringManipulator.UnpackWith(string(b), opts)
+			out = []byte(s)
+			if err == nil && opts.Format == stringManipulator.FormatEscaped {
+				warnLossy(stderr, b)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}))
+}
+
+// flagSet reports whether the flag called name was given on the command line
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(fl *flag.Flag) {
+		set = set || fl.Name == name
+	})
+	return set
+}
+
+// warnLossy reports on stderr when the escaped header of b records that normalization or case folding changed the input
+func warnLossy(stderr io.Writer, b []byte) {
+	if h, _ := stringManipulator.ParseEscapedHeader(string(b)); h.Lossy() {
+		fmt.Fprintf(stderr, "rle unpack: input was normalized before compression (%v, case-folded: %t); the output may differ from the original\n", h.Normalize, h.CaseFold)
+	}
+}
+
+// armor returns the Armor named by the -armor flag of compress
+func armor(name string) (stringManipulator.Armor, error) {
+	for _, a := range []stringManipulator.Armor{stringManipulator.ArmorNone, stringManipulator.ArmorBase64URL, stringManipulator.ArmorAscii85, stringManipulator.ArmorQuoted} {
+		if a.String() == name {
+			return a, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown armor %q", name)
+}
+
+// armored returns fn with its output collected and written to the real output in armor a
+func armored(a stringManipulator.Armor, fn func(io.Reader, io.Writer) error) func(io.Reader, io.Writer) error {
+	if a == stringManipulator.ArmorNone {
+		return fn
+	}
+	return func(r io.Reader, w io.Writer) error {
+		var buf bytes.Buffer
+		if err := fn(r, &buf); err != nil {
+			return err
+		}
+		out, err := a.Encode(buf.Bytes())
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+}
+
+// dearmored returns fn with the armor detected at the start of its input removed when detect is set.
+// Input without armor is passed on as a stream.
+func dearmored(detect bool, fn func(io.Reader, io.Writer) error) func(io.Reader, io.Writer) error {
+	if !detect {
+		return fn
+	}
+	return func(r io.Reader, w io.Writer) error {
+		br := bufio.NewReader(r)
+		prefix, _ := br.Peek(len(stringManipulator.Base64URLPrefix)) // the longest prefix
+		if stringManipulator.DetectArmor(prefix) == stringManipulator.ArmorNone {
+			return fn(br, w)
+		}
+		b, err := ioutil.ReadAll(br)
+		if err != nil {
+			return err
+		}
+		if b, _, err = stringManipulator.Dearmor(b); err != nil {
+			return err
+		}
+		return fn(bytes.NewReader(b), w)
+	}
+}
+
+// process opens the input and output, runs fn between them and reports statistics
+func process(input, output string, stdin io.Reader, stdout, stderr io.Writer, stats bool, fn func(io.Reader, io.Writer) error) error {
+	r := stdin
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+
+	w := stdout
+	var file *os.File
+	if output != "" && output != "-" {
+		var err error
+		if file, err = os.Create(output); err != nil {
+			return err
+		}
+		w = file
+	}
+
+	in, out := &countingReader{r: r}, &countingWriter{w: w}
+	err := fn(in, out)
+	if file != nil {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if stats {
+		ratio := 0.0
+		if in.n > 0 {
+			ratio = float64(out.n) / float64(in.n)
+		}
+		fmt.Fprintf(stderr, "in: %d bytes, out: %d bytes, ratio: %.3f\n", in.n, out.n, ratio)
+	}
+	return nil
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
 }
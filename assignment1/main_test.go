@@ -0,0 +1,346 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+	"github.com/stretchr/testify/assert"
+)
+
+// runCLI runs the command line with stdin and returns the exit code, stdout and stderr
+func runCLI(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCompressCommand(t *testing.T) {
+	t.Log("compress should write the compressed input to stdout.")
+	code, stdout, _ := runCLI("aaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj", "compress")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "a6€5c3d3a2 ef2gj9j3", stdout)
+
+	code, stdout, _ = runCLI("aaaa1111", "compress", "-format", "escaped", "-count", "delimited")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `\1a{4}\1{4}`, stdout)
+}
+
+func TestUnpackCommand(t *testing.T) {
+	t.Log("unpack should write the unpacked input to stdout.")
+	code, stdout, _ := runCLI("a6€5c3", "unpack")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "aaaaaa€€€€€ccc", stdout)
+
+	code, stdout, _ = runCLI(`\1a{4}\1{4}`, "unpack", "--format=escaped", "--count=delimited")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "aaaa1111", stdout)
+}
+
+func TestUnpackCommandFails(t *testing.T) {
+	t.Log("unpack should exit non-zero with a message when the input cannot be decoded.")
+	code, stdout, stderr := runCLI("9a", "unpack", "-strict")
+	assert.Equal(t, 1, code)
+	assert.Empty(t, stdout)
+	assert.Contains(t, stderr, "invalid input at byte 0 (rune 0)")
+
+	code, _, stderr = runCLI(`\1\`, "unpack", "-format", "escaped")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "expected a character after the escape")
+}
+
+func TestFilesAndStats(t *testing.T) {
+	t.Log("compress and unpack should read and write files and report statistics.")
+	dir, err := ioutil.TempDir("", "rle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	input, compressed, output := filepath.Join(dir, "in"), filepath.Join(dir, "in.rle"), filepath.Join(dir, "out")
+	data := append(bytes.Repeat([]byte{0}, 1000), 0xff, 0xfe)
+	assert.NoError(t, ioutil.WriteFile(input, data, 0644))
+
+	code, _, stderr := runCLI("", "compress", "-format", "packbits", "-stats", "-o", compressed, input)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "in: 1002 bytes, out: 19 bytes, ratio: 0.019\n", stderr)
+
+	code, _, _ = runCLI("", "unpack", "-format", "packbits", "-o", output, compressed)
+	assert.Equal(t, 0, code)
+	result, err := ioutil.ReadFile(output)
+	assert.NoError(t, err)
+	assert.Equal(t, data, result)
+}
+
+func TestCodecFlag(t *testing.T) {
+	t.Log("compress and unpack should use the codec named by -codec.")
+	input := strings.Repeat("abcdefgh", 100)
+	code, compressed, stderr := runCLI(input, "compress", "-codec", "auto")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "rle compress: chose codec lz77\n", stderr)
+
+	code, stdout, _ := runCLI(compressed, "unpack", "-codec", "lz77")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, input, stdout)
+
+	code, _, stderr = runCLI(input, "compress", "-codec", "zip")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown codec "zip"`)
+}
+
+func TestSealFlag(t *testing.T) {
+	t.Log("compress -seal should write a container that unpack -seal validates.")
+	input := strings.Repeat("abcdefgh", 100)
+	code, sealed, _ := runCLI(input, "compress", "-seal", "-codec", "auto")
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(sealed, "\x89RLE"))
+
+	code, stdout, _ := runCLI(sealed, "unpack", "-seal")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, input, stdout)
+
+	code, _, stderr := runCLI(sealed[:len(sealed)-1], "unpack", "-seal")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "checksum mismatch")
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"compress", "-seal", "-format", "escaped"}, "-seal cannot be combined with -format"},
+		{[]string{"compress", "-codec", "lz77", "-unit", "grapheme"}, "-codec cannot be combined with -unit"},
+		{[]string{"compress", "-parallel", "-count", "varint"}, "-parallel cannot be combined with -count"},
+		{[]string{"unpack", "-seal", "-fold"}, "-seal cannot be combined with -fold"},
+		{[]string{"unpack", "-codec", "rle", "-strict"}, "-codec cannot be combined with -strict"},
+	}
+	for _, test := range tests {
+		t.Logf("%v should fail, as the codec ignores the format flags.", test.args)
+		code, _, stderr := runCLI(input, test.args...)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, test.expected)
+	}
+}
+
+func TestParallelFlag(t *testing.T) {
+	t.Log("compress -parallel should write chunks that unpack -parallel decodes.")
+	input := strings.Repeat("aaaa€€€€bbbb", 100)
+	code, chunked, _ := runCLI(input, "compress", "-parallel", "-chunk-size", "100", "-workers", "3", "-codec", "lz77")
+	assert.Equal(t, 0, code)
+	assert.True(t, strings.HasPrefix(chunked, "\x89RLC"))
+
+	code, stdout, _ := runCLI(chunked, "unpack", "-parallel")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, input, stdout)
+
+	code, _, stderr := runCLI(input, "compress", "-parallel", "-seal")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "cannot be combined")
+}
+
+func TestEntityUnit(t *testing.T) {
+	t.Log("-unit entity should repeat whole character references.")
+	code, stdout, _ := runCLI("&#39;&#39;&#39;", "compress", "-format", "escaped", "-unit", "entity")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "~1&#39;3", stdout)
+
+	code, stdout, _ = runCLI(stdout, "unpack", "-format", "escaped", "-unit", "entity")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "&#39;&#39;&#39;", stdout)
+}
+
+func TestMaxOutputFlag(t *testing.T) {
+	t.Log("unpack should fail cleanly on a count larger than -max-output.")
+	code, _, stderr := runCLI("\\1a9223372036854775807", "unpack", "-format", "escaped")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "output limit")
+
+	code, _, stderr = runCLI("\\1a5", "unpack", "-format", "escaped", "-max-output", "4")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "output limit of 4 bytes")
+	code, stdout, _ := runCLI("\\1a5", "unpack", "-format", "escaped", "-max-output", "5")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "aaaaa", stdout)
+}
+
+func TestArmorFlag(t *testing.T) {
+	input := "aaaaaa\u20ac\u20ac\u20ac\u20ac\u20accccdddaa"
+	for _, armor := range []string{"base64url", "ascii85", "quoted"} {
+		t.Logf("compress -armor %s should write text that unpack detects, with or without a trailing newline.", armor)
+		code, armored, _ := runCLI(input, "compress", "-codec", "huffman", "-armor", armor)
+		assert.Equal(t, 0, code)
+		for _, c := range armored {
+			assert.True(t, c > ' ' && c < 0x7f, "-armor %s wrote %q", armor, c)
+		}
+		for _, text := range []string{armored, armored + "\n"} {
+			code, stdout, _ := runCLI(text, "unpack", "-codec", "huffman")
+			assert.Equal(t, 0, code)
+			assert.Equal(t, input, stdout)
+		}
+	}
+
+	t.Log("unpack -armor none should read legacy output starting with an armor prefix as it is.")
+	for _, input := range []string{"qp:x", "<~ab~>", "qp:::aaa"} {
+		code, compressed, _ := runCLI(input, "compress")
+		assert.Equal(t, 0, code)
+		code, stdout, _ := runCLI(compressed, "unpack", "-armor", "none")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, input, stdout)
+	}
+
+	code, _, stderr := runCLI("qp:=4", "unpack")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "malformed input")
+
+	code, _, stderr = runCLI("a", "compress", "-armor", "base32")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown armor "base32"`)
+}
+
+func TestPBMCommand(t *testing.T) {
+	image := "P1\n# a 4x3 box\n4 3\n1 1 1 1\n1 0 0 1\n1 1 1 1\n"
+	t.Log("pbm should write the scanline runs of an image, and pbm -d should decode them.")
+	code, runs, _ := runCLI(image, "pbm")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "\x04\x03\x00\x04\x00\x01\x02\x01\x00\x04", runs)
+
+	code, stdout, _ := runCLI(runs, "pbm", "-d")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "P4\n4 3\n\xf0\x90\xf0", stdout)
+	code, stdout, _ = runCLI(runs, "pbm", "-d", "-plain")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "P1\n4 3\n1111\n1001\n1111\n", stdout)
+
+	t.Log("-codec pbm should return the original bytes.")
+	code, compressed, _ := runCLI(image, "compress", "-codec", "pbm")
+	assert.Equal(t, 0, code)
+	code, stdout, _ = runCLI(compressed, "unpack", "-codec", "pbm")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, image, stdout)
+
+	code, _, stderr := runCLI("not an image", "pbm")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "invalid PBM image")
+}
+
+func TestUnpackAutoFormat(t *testing.T) {
+	input := "aaaaaa\u20ac\u20ac\u20ac\u20ac\u20accccdddaa 1111"
+	tests := [][]string{
+		{"compress", "-format", "escaped"},
+		{"compress", "-format", "tokens"},
+		{"compress", "-seal"},
+		{"compress", "-parallel", "-chunk-size", "8"},
+	}
+	for _, args := range tests {
+		t.Logf("unpack without -format should detect the output of %v.", args)
+		code, compressed, _ := runCLI(input, args...)
+		assert.Equal(t, 0, code)
+		code, stdout, _ := runCLI(compressed, "unpack")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, input, stdout)
+	}
+
+	t.Log("unpack without -format should return plain text unchanged.")
+	code, stdout, _ := runCLI("room 42, page 3 of 4", "unpack")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "room 42, page 3 of 4", stdout)
+
+	t.Log("unpack should read the legacy format when given its options without -format.")
+	code, stdout, _ = runCLI("a3b", "unpack", "-strict")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "aaab", stdout)
+
+	code, _, stderr := runCLI("a", "compress", "-format", "auto")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "only supported by unpack")
+	for _, flag := range []string{"-strict", "-fold"} {
+		code, _, stderr = runCLI("a", "unpack", "-format", "auto", flag)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "-format auto cannot be combined")
+	}
+}
+
+func TestDefaultRoundTrip(t *testing.T) {
+	for _, input := range []string{"aaabbb", "plain text", "aaaaaa\u20ac\u20ac\u20ac\u20ac\u20accccdddaa"} {
+		t.Logf("unpack should reverse compress without flags when the input is %q.", input)
+		code, compressed, _ := runCLI(input, "compress")
+		assert.Equal(t, 0, code)
+		code, stdout, _ := runCLI(compressed, "unpack")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, input, stdout)
+	}
+
+	for _, input := range []string{"hho", "aaab", "hello"} {
+		t.Logf("unpack without flags should refuse to guess when compressing %q writes a single count, and -format legacy should unpack it.", input)
+		code, compressed, _ := runCLI(input, "compress")
+		assert.Equal(t, 0, code)
+		code, _, stderr := runCLI(compressed, "unpack")
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stderr, "pass -format legacy")
+		code, stdout, _ := runCLI(compressed, "unpack", "-format", "legacy")
+		assert.Equal(t, 0, code)
+		assert.Equal(t, input, stdout)
+	}
+}
+
+func TestNormalizeFlags(t *testing.T) {
+	t.Log("compress -normalize and -fold should record the change, and unpack should warn about it.")
+	code, stdout, _ := runCLI("E\u0301e\u0301", "compress", "-format", "escaped", "-normalize", "nfc", "-fold")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "\\2f\u00e92", stdout)
+
+	code, stdout, stderr := runCLI(stdout, "unpack", "-format", "escaped")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "\u00e9\u00e9", stdout)
+	assert.Contains(t, stderr, "normalized before compression (NFC, case-folded: true)")
+
+	code, _, stderr = runCLI("a", "compress", "-normalize", "nfc")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, "invalid options")
+}
+
+func TestTokensFormat(t *testing.T) {
+	t.Log("compress -format tokens should collapse repeated tokens split by -split.")
+	code, stdout, _ := runCLI("a;b;b;b", "compress", "-format", "tokens", "-split", "regexp:;")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, `\T1a|;|b*3*;||`, stdout)
+
+	code, stdout, _ = runCLI(stdout, "unpack", "-format", "tokens")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "a;b;b;b", stdout)
+
+	code, _, stderr := runCLI("a", "compress", "-format", "tokens", "-split", "commas")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, stderr, `unknown splitter "commas"`)
+}
+
+func TestAnalyzeCommand(t *testing.T) {
+	t.Log("analyze should print the report as a table.")
+	code, stdout, _ := runCLI("aaaaaaaaaaaab1", "analyze")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, stdout, "expansion risk  low\n")
+	assert.Contains(t, stdout, "\"a\"           12      0\n")
+	assert.Regexp(t, `(?m)^legacy +6 +0\.429 +false$`, stdout)
+
+	t.Log("analyze -json should print the report as JSON.")
+	code, stdout, _ = runCLI("a1b2", "analyze", "-json")
+	assert.Equal(t, 0, code)
+	var report stringManipulator.Report
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &report))
+	assert.Equal(t, stringManipulator.RiskHigh, report.Risk)
+	assert.Equal(t, 4, report.Runs)
+}
+
+func TestUsage(t *testing.T) {
+	t.Log("rle should exit with status 2 on an invalid command line.")
+	code, _, stderr := runCLI("")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, stderr, "usage: rle")
+
+	code, _, _ = runCLI("", "explode")
+	assert.Equal(t, 2, code)
+
+	code, _, _ = runCLI("", "compress", "-bogus")
+	assert.Equal(t, 2, code)
+}
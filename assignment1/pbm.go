@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/kindaqt/assignment1/pbm"
+)
+
+func pbmCommand(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	var output string
+	var decode, plain, stats bool
+	fs := flag.NewFlagSet("rle pbm", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&output, "o", "", "write output to `file` instead of stdout")
+	fs.BoolVar(&decode, "d", false, "decode scanline runs back to a PBM image")
+	fs.BoolVar(&plain, "plain", false, "with -d, write a plain P1 image instead of a raw P4 image")
+	fs.BoolVar(&stats, "stats", false, "report sizes and the compression ratio on stderr")
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return err
+		}
+		return errUsage // already reported by fs
+	}
+	input := "-"
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		input = fs.Arg(0)
+	default:
+		fmt.Fprintf(stderr, "%s: too many arguments\n", fs.Name())
+		fs.Usage()
+		return errUsage
+	}
+
+	return process(input, output, stdin, stdout, stderr, stats, func(r io.Reader, w io.Writer) error {
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		if !decode {
+			img, err := pbm.Decode(b)
+			if err != nil {
+				return err
+			}
+			_, err = w.Write(pbm.EncodeRuns(img))
+			return err
+		}
+
+		img, err := pbm.DecodeRuns(b)
+		if err != nil {
+			return err
+		}
+		if plain {
+			_, err = w.Write(pbm.EncodePlain(img))
+		} else {
+			_, err = w.Write(pbm.Encode(img))
+		}
+		return err
+	})
+}
@@ -0,0 +1,91 @@
+package pbm
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+)
+
+// ID is the codec ID of Codec
+const ID byte = 5
+
+// Codec compresses PBM images with EncodeRuns. It is registered with stringManipulator when this package is imported.
+// A Codec must round-trip any input, so images are only run-length encoded when Encode or EncodePlain would write them
+// byte for byte; other input, such as an image with comments, is stored with stringManipulator.PackBits.
+var Codec stringManipulator.Codec = codec{}
+
+func init() {
+	if err := stringManipulator.Register(Codec); err != nil {
+		panic(err)
+	}
+}
+
+// Modes written in the first byte of the codec's output
+const (
+	modeStored byte = iota // PackBits of the input
+	modeRaw                // runs of a P4 image written by Encode
+	modePlain              // runs of a P1 image written by EncodePlain
+)
+
+// maxInt is the largest int, the limit of Decode
+const maxInt = int(^uint(0) >> 1)
+
+type codec struct{}
+
+func (codec) Name() string { return "pbm" }
+func (codec) ID() byte     { return ID }
+
+func (codec) Encode(src []byte) ([]byte, error) {
+	stored := append([]byte{modeStored}, stringManipulator.CompressBytes(src)...)
+	b, err := Decode(src)
+	if err != nil {
+		return stored, nil
+	}
+
+	var dst []byte
+	switch {
+	case bytes.Equal(src, Encode(b)):
+		dst = append([]byte{modeRaw}, EncodeRuns(b)...)
+	case bytes.Equal(src, EncodePlain(b)):
+		dst = append([]byte{modePlain}, EncodeRuns(b)...)
+	}
+	if dst == nil || len(stored) < len(dst) {
+		return stored, nil
+	}
+	return dst, nil
+}
+
+func (c codec) Decode(src []byte) ([]byte, error) {
+	return c.DecodeLimit(src, maxInt)
+}
+
+// DecodeLimit implements stringManipulator.LimitedDecoder. The size of an image is known from the first bytes of its runs,
+// so an image longer than limit once written is rejected before it is decoded.
+func (codec) DecodeLimit(src []byte, limit int) ([]byte, error) {
+	if len(src) < 1 {
+		return nil, fmt.Errorf("%w: PBM codec data is empty", stringManipulator.ErrMalformed)
+	}
+	switch src[0] {
+	case modeStored:
+		// PackBits output is bounded by its input, and the caller checks it against limit
+		return stringManipulator.UnpackBytes(src[1:])
+	case modeRaw, modePlain:
+		width, height, _, err := runsSize(src[1:])
+		if err != nil {
+			return nil, err
+		}
+		if encodedLen(width, height, src[0] == modePlain) > limit {
+			return nil, stringManipulator.ErrLength
+		}
+		b, err := DecodeRuns(src[1:])
+		if err != nil {
+			return nil, err
+		}
+		if src[0] == modePlain {
+			return EncodePlain(b), nil
+		}
+		return Encode(b), nil
+	}
+	return nil, fmt.Errorf("%w: unknown PBM codec mode %d", stringManipulator.ErrMalformed, src[0])
+}
@@ -0,0 +1,73 @@
+package pbm
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodecRegistered(t *testing.T) {
+	t.Log("Importing pbm should register Codec.")
+	c, ok := stringManipulator.CodecByName("pbm")
+	assert.True(t, ok)
+	assert.Equal(t, Codec, c)
+	c, ok = stringManipulator.CodecByID(ID)
+	assert.True(t, ok)
+	assert.Equal(t, Codec, c)
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	b := form(300, 200, 2)
+	tests := []struct {
+		name  string
+		input []byte
+		mode  byte
+	}{
+		{"P4", Encode(b), modeRaw},
+		{"P1", EncodePlain(b), modePlain},
+		{"P1 with comments", []byte(letterJ), modeStored},
+		{"empty", nil, modeStored},
+		{"text", []byte("aaaaaaaaaabbb"), modeStored},
+		{"empty P4", []byte("P4\n0 0\n"), modeRaw},
+	}
+	for _, test := range tests {
+		t.Logf("Codec should encode %s in mode %d and decode it byte for byte.", test.name, test.mode)
+		out, err := Codec.Encode(test.input)
+		assert.NoError(t, err)
+		assert.Equal(t, test.mode, out[0])
+		decoded, err := Codec.Decode(out)
+		assert.NoError(t, err)
+		assert.Equal(t, string(test.input), string(decoded))
+	}
+
+	t.Log("Codec should reject unknown modes and empty input.")
+	for _, input := range []string{"", "\x03"} {
+		_, err := Codec.Decode([]byte(input))
+		assert.Error(t, err)
+	}
+}
+
+func TestCodecDecodeLimit(t *testing.T) {
+	b := form(300, 200, 2)
+	for _, mode := range []byte{modeRaw, modePlain} {
+		src := append([]byte{mode}, EncodeRuns(b)...)
+		size := encodedLen(b.Width, b.Height, mode == modePlain)
+		t.Logf("DecodeLimit() should reject mode %d output of %d bytes before decoding it when the limit is one byte less.", mode, size)
+		_, err := Codec.(stringManipulator.LimitedDecoder).DecodeLimit(src, size-1)
+		assert.Equal(t, stringManipulator.ErrLength, err)
+		out, err := Codec.(stringManipulator.LimitedDecoder).DecodeLimit(src, size)
+		assert.NoError(t, err)
+		assert.Len(t, out, size)
+	}
+
+	t.Log("Open() should reject a container whose PBM payload describes a larger image than its header length.")
+	blob, err := stringManipulator.Seal(Codec, Encode(b))
+	assert.NoError(t, err)
+	blob = append(blob[:18:18], append([]byte{modeRaw}, EncodeRuns(New(1<<13, 1<<13))...)...)
+	binary.BigEndian.PutUint32(blob[14:18], crc32.ChecksumIEEE(append(blob[:14:14], blob[18:]...)))
+	_, err = stringManipulator.Open(blob)
+	assert.Equal(t, stringManipulator.ErrLength, err)
+}
@@ -0,0 +1,204 @@
+// Package pbm reads and writes monochrome PBM images and run-length encodes them scanline by scanline,
+// like the one-dimensional coding of CCITT fax (T.4), for scanned black-and-white documents.
+package pbm
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrFormat is returned when data is not a valid PBM image
+var ErrFormat = errors.New("pbm: invalid PBM image")
+
+// Magic numbers of the two PBM variants
+const (
+	PlainMagic = "P1" // pixels as the characters '0' and '1'
+	RawMagic   = "P4" // pixels packed eight to a byte
+)
+
+// plainLineLen is the longest line EncodePlain writes, as the PBM specification recommends
+const plainLineLen = 70
+
+// Bitmap is a monochrome image. A set bit is a black pixel, as in PBM.
+type Bitmap struct {
+	Width, Height int
+	Pix           []byte // Height rows of Stride() bytes, most significant bit first; the bits past Width in a row are zero
+}
+
+// New returns a white bitmap of the given size
+func New(width, height int) *Bitmap {
+	b := &Bitmap{Width: width, Height: height}
+	b.Pix = make([]byte, b.Stride()*height)
+	return b
+}
+
+// Stride returns the number of bytes in a row
+func (b *Bitmap) Stride() int {
+	return (b.Width + 7) / 8
+}
+
+// Row returns the bytes of row y
+func (b *Bitmap) Row(y int) []byte {
+	return b.Pix[y*b.Stride() : (y+1)*b.Stride()]
+}
+
+// Black reports whether the pixel at (x, y) is black
+func (b *Bitmap) Black(x, y int) bool {
+	return b.Pix[y*b.Stride()+x/8]&(0x80>>uint(x%8)) != 0
+}
+
+// Set makes the pixel at (x, y) black or white
+func (b *Bitmap) Set(x, y int, black bool) {
+	i, mask := y*b.Stride()+x/8, byte(0x80>>uint(x%8))
+	if black {
+		b.Pix[i] |= mask
+	} else {
+		b.Pix[i] &^= mask
+	}
+}
+
+// Decode reads a P1 or P4 image. Anything after the first image is ignored.
+// The returned error wraps ErrFormat.
+func Decode(data []byte) (*Bitmap, error) {
+	if len(data) < 2 || string(data[:2]) != PlainMagic && string(data[:2]) != RawMagic {
+		return nil, fmt.Errorf("%w: missing %s or %s magic number", ErrFormat, PlainMagic, RawMagic)
+	}
+	p := parser{data: data, i: 2}
+	width, err := p.number("width")
+	if err != nil {
+		return nil, err
+	}
+	height, err := p.number("height")
+	if err != nil {
+		return nil, err
+	}
+	if width == 0 && height > maxEmptyHeight {
+		return nil, fmt.Errorf("%w: zero-width image is %d lines tall", ErrFormat, height)
+	}
+
+	if string(data[:2]) == RawMagic {
+		// A single white space character separates the header from the raster
+		if p.i >= len(data) || !isSpace(data[p.i]) {
+			return nil, fmt.Errorf("%w: missing white space after the header", ErrFormat)
+		}
+		raster := data[p.i+1:]
+		stride := (width + 7) / 8
+		if stride > 0 && height > len(raster)/stride {
+			return nil, fmt.Errorf("%w: raster is truncated", ErrFormat)
+		}
+		b := New(width, height)
+		copy(b.Pix, raster)
+		b.clearPadding()
+		return b, nil
+	}
+
+	// Each pixel takes at least one byte, which bounds the allocation by the size of data
+	if width > 0 && height > (len(data)-p.i)/width {
+		return nil, fmt.Errorf("%w: raster is truncated", ErrFormat)
+	}
+	b := New(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p.skip()
+			if p.i >= len(data) || data[p.i] != '0' && data[p.i] != '1' {
+				return nil, fmt.Errorf("%w: expected a pixel at offset %d", ErrFormat, p.i)
+			}
+			b.Set(x, y, data[p.i] == '1')
+			p.i++
+		}
+	}
+	return b, nil
+}
+
+// clearPadding zeroes the bits past Width in every row, which PBM leaves undefined
+func (b *Bitmap) clearPadding() {
+	if b.Width%8 == 0 {
+		return
+	}
+	mask := byte(0xff) << uint(8-b.Width%8)
+	for y := 0; y < b.Height; y++ {
+		b.Row(y)[b.Stride()-1] &= mask
+	}
+}
+
+// parser reads the header of a PBM image
+type parser struct {
+	data []byte
+	i    int
+}
+
+// skip moves past white space and comments, which run from '#' to the end of the line
+func (p *parser) skip() {
+	for p.i < len(p.data) {
+		switch c := p.data[p.i]; {
+		case isSpace(c):
+			p.i++
+		case c == '#':
+			for p.i < len(p.data) && p.data[p.i] != '\n' && p.data[p.i] != '\r' {
+				p.i++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// number reads a decimal header field
+func (p *parser) number(name string) (int, error) {
+	p.skip()
+	start := p.i
+	for p.i < len(p.data) && p.data[p.i] >= '0' && p.data[p.i] <= '9' {
+		p.i++
+	}
+	n, err := strconv.Atoi(string(p.data[start:p.i]))
+	if err != nil || n > maxPixels {
+		return 0, fmt.Errorf("%w: invalid %s at offset %d", ErrFormat, name, start)
+	}
+	return n, nil
+}
+
+// isSpace reports whether c is white space in a PBM header
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\v' || c == '\f' || c == '\r'
+}
+
+// Encode writes b as a P4 image
+func Encode(b *Bitmap) []byte {
+	header := fmt.Sprintf("%s\n%d %d\n", RawMagic, b.Width, b.Height)
+	return append([]byte(header), b.Pix...)
+}
+
+// EncodePlain writes b as a P1 image with one character per pixel, starting a new line for every row
+// and after every 70 characters
+func EncodePlain(b *Bitmap) []byte {
+	header := fmt.Sprintf("%s\n%d %d\n", PlainMagic, b.Width, b.Height)
+	dst := make([]byte, 0, encodedLen(b.Width, b.Height, true))
+	dst = append(dst, header...)
+	for y := 0; y < b.Height; y++ {
+		for x := 0; x < b.Width; x++ {
+			if x > 0 && x%plainLineLen == 0 {
+				dst = append(dst, '\n')
+			}
+			if b.Black(x, y) {
+				dst = append(dst, '1')
+			} else {
+				dst = append(dst, '0')
+			}
+		}
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// encodedLen returns the length of the output of Encode, or of EncodePlain when plain is set, for an image of the given size
+func encodedLen(width, height int, plain bool) int {
+	magic, row := RawMagic, (width+7)/8
+	if plain {
+		magic, row = PlainMagic, width+1
+		if width > 0 {
+			row += (width - 1) / plainLineLen
+		}
+	}
+	return len(fmt.Sprintf("%s\n%d %d\n", magic, width, height)) + height*row
+}
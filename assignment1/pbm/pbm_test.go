@@ -0,0 +1,81 @@
+package pbm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// letterJ is a 6x10 image of a "J" from the netpbm documentation
+const letterJ = `P1
+# This is an example bitmap of the letter "J"
+6 10
+0 0 0 0 1 0
+0 0 0 0 1 0
+0 0 0 0 1 0
+0 0 0 0 1 0
+0 0 0 0 1 0
+0 0 0 0 1 0
+1 0 0 0 1 0
+0 1 1 1 0 0
+0 0 0 0 0 0
+0 0 0 0 0 0
+`
+
+func TestDecodePlain(t *testing.T) {
+	t.Log("Decode() should read a P1 image with comments and spaces between pixels.")
+	b, err := Decode([]byte(letterJ))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, b.Width)
+	assert.Equal(t, 10, b.Height)
+	assert.Equal(t, []byte{0x08, 0x08, 0x08, 0x08, 0x08, 0x08, 0x88, 0x70, 0x00, 0x00}, b.Pix)
+	assert.True(t, b.Black(4, 0))
+	assert.False(t, b.Black(5, 0))
+
+	t.Log("EncodePlain() should write one row per line.")
+	assert.Equal(t, "P1\n6 10\n000010\n000010\n000010\n000010\n000010\n000010\n100010\n011100\n000000\n000000\n", string(EncodePlain(b)))
+}
+
+func TestDecodeRaw(t *testing.T) {
+	t.Log("Decode() should read a P4 image and clear the padding bits.")
+	b, err := Decode([]byte("P4 #comment\n10 2\n\xff\xff\x00\x7f"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xc0, 0x00, 0x40}, b.Pix)
+	assert.Equal(t, "P4\n10 2\n\xff\xc0\x00\x40", string(Encode(b)))
+
+	t.Log("Decode() should read what Encode() and EncodePlain() write.")
+	for _, data := range [][]byte{Encode(b), EncodePlain(b)} {
+		decoded, err := Decode(data)
+		assert.NoError(t, err)
+		assert.Equal(t, b, decoded)
+	}
+}
+
+func TestPlainLineLength(t *testing.T) {
+	t.Log("EncodePlain() should wrap rows longer than 70 pixels.")
+	b := New(150, 1)
+	b.Set(149, 0, true)
+	out := string(EncodePlain(b))
+	assert.Equal(t, "P1\n150 1\n", out[:9])
+	assert.Equal(t, byte('\n'), out[9+70])
+	assert.Equal(t, byte('\n'), out[9+141])
+	assert.Equal(t, "01\n", out[len(out)-3:])
+}
+
+func TestSet(t *testing.T) {
+	t.Log("Set() should change a single pixel.")
+	b := New(9, 2)
+	b.Set(8, 1, true)
+	assert.Equal(t, []byte{0, 0, 0, 0x80}, b.Pix)
+	b.Set(8, 1, false)
+	assert.Equal(t, []byte{0, 0, 0, 0}, b.Pix)
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	for _, input := range []string{"", "P2\n1 1\n0", "P1\n", "P1\n2", "P1\nx 1\n", "P1\n2 2\n010", "P1\n2 1\n02", "P4\n8 2\n\x00", "P4\n8 1", "P4\n8 1\x00", "P1\n0 1073741824\n", "P4\n0 65537\n"} {
+		t.Logf("Decode(%q) should fail.", input)
+		_, err := Decode([]byte(input))
+		assert.True(t, errors.Is(err, ErrFormat), "got %v", err)
+	}
+}
@@ -0,0 +1,128 @@
+package pbm
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+)
+
+// maxPixels is the largest image the decoders accept, since a few bytes of runs can describe a huge image:
+// 128 Mi pixels, which take 16 MiB as a bitmap and about 130 MB written by EncodePlain
+const maxPixels = 1 << 27
+
+// maxEmptyHeight is the tallest zero-width image the decoders accept. Such an image has no pixels to bound its height
+// by the size of the input, yet EncodePlain writes a line for each row.
+const maxEmptyHeight = 1 << 16
+
+// Run format: the uvarint width and height, then for every scanline the uvarint lengths of its runs,
+// alternating between white and black and starting with white, so a line starting with black opens with a white run of zero.
+// The runs of a line add up to the width, which ends the line.
+//
+// EncodeRuns returns the runs of b
+func EncodeRuns(b *Bitmap) []byte {
+	var varint [binary.MaxVarintLen64]byte
+	dst := append([]byte(nil), varint[:binary.PutUvarint(varint[:], uint64(b.Width))]...)
+	dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(b.Height))]...)
+	if b.Width == 0 {
+		return dst
+	}
+
+	for y := 0; y < b.Height; y++ {
+		row := b.Row(y)
+		black, run := false, 0
+		for x := 0; x < b.Width; {
+			// Whole bytes of the current colour are common in scans and are counted at once
+			if x%8 == 0 && x+8 <= b.Width && row[x/8] == fill(black) {
+				run += 8
+				x += 8
+				continue
+			}
+			if (row[x/8]&(0x80>>uint(x%8)) != 0) != black {
+				dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(run))]...)
+				black, run = !black, 0
+			}
+			run++
+			x++
+		}
+		dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(run))]...)
+	}
+	return dst
+}
+
+// fill returns a byte of eight pixels of one colour
+func fill(black bool) byte {
+	if black {
+		return 0xff
+	}
+	return 0
+}
+
+// DecodeRuns reverses EncodeRuns. The returned error wraps stringManipulator.ErrMalformed.
+func DecodeRuns(data []byte) (*Bitmap, error) {
+	width, height, n, err := runsSize(data)
+	if err != nil {
+		return nil, err
+	}
+	data = data[n:]
+
+	b := New(width, height)
+	for y := 0; y < b.Height; y++ {
+		row := b.Row(y)
+		black := false
+		for x := 0; x < b.Width; black = !black {
+			n, k := binary.Uvarint(data)
+			if k <= 0 {
+				return nil, fmt.Errorf("%w: PBM line %d is truncated", stringManipulator.ErrMalformed, y)
+			}
+			if n > uint64(b.Width-x) {
+				return nil, fmt.Errorf("%w: PBM line %d is longer than the width", stringManipulator.ErrMalformed, y)
+			}
+			data = data[k:]
+			if black {
+				fillRun(row, x, int(n))
+			}
+			x += int(n)
+		}
+	}
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%w: %d bytes after the last PBM line", stringManipulator.ErrMalformed, len(data))
+	}
+	return b, nil
+}
+
+// runsSize reads the size at the start of the runs in data, checking it before anything is allocated.
+// It also returns the length of the size in bytes.
+func runsSize(data []byte) (width, height, n int, err error) {
+	w, k := binary.Uvarint(data)
+	if k <= 0 {
+		return 0, 0, 0, fmt.Errorf("%w: PBM width is invalid", stringManipulator.ErrMalformed)
+	}
+	h, j := binary.Uvarint(data[k:])
+	if j <= 0 {
+		return 0, 0, 0, fmt.Errorf("%w: PBM height is invalid", stringManipulator.ErrMalformed)
+	}
+	n = k + j
+	// Every line takes at least one byte unless the image is empty
+	if w > maxPixels || h > maxPixels || w == 0 && h > maxEmptyHeight ||
+		w > 0 && (h > uint64(len(data)-n) || w*h > maxPixels) {
+		return 0, 0, 0, fmt.Errorf("%w: PBM size %dx%d is too large", stringManipulator.ErrMalformed, w, h)
+	}
+	return int(w), int(h), n, nil
+}
+
+// fillRun makes n pixels of row black from x on
+func fillRun(row []byte, x, n int) {
+	for ; n > 0 && x%8 != 0; n-- {
+		row[x/8] |= 0x80 >> uint(x%8)
+		x++
+	}
+	for ; n >= 8; n -= 8 {
+		row[x/8] = 0xff
+		x += 8
+	}
+	for ; n > 0; n-- {
+		row[x/8] |= 0x80 >> uint(x%8)
+		x++
+	}
+}
@@ -0,0 +1,92 @@
+package pbm
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/kindaqt/assignment1/stringManipulator"
+	"github.com/stretchr/testify/assert"
+)
+
+// form returns a scanned-form-like image: white with a few ruled lines, boxes and specks
+func form(width, height int, seed int64) *Bitmap {
+	r := rand.New(rand.NewSource(seed))
+	b := New(width, height)
+	for y := 10; y < height; y += 40 {
+		for x := 5; x < width-5; x++ {
+			b.Set(x, y, true)
+		}
+	}
+	for y := 0; y < height; y++ {
+		b.Set(5, y, true)
+		if r.Intn(4) == 0 {
+			b.Set(r.Intn(width), y, true)
+		}
+	}
+	return b
+}
+
+func TestEncodeRuns(t *testing.T) {
+	b, err := Decode([]byte("P1\n10 3\n0000000000\n1100000011\n1111111111\n"))
+	assert.NoError(t, err)
+
+	t.Log("EncodeRuns() should write alternating runs starting with white.")
+	assert.Equal(t, []byte{10, 3, 10, 0, 2, 6, 2, 0, 10}, EncodeRuns(b))
+
+	decoded, err := DecodeRuns(EncodeRuns(b))
+	assert.NoError(t, err)
+	assert.Equal(t, b, decoded)
+}
+
+func TestRunsRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		b := New(r.Intn(40), r.Intn(5))
+		for y := 0; y < b.Height; y++ {
+			black := false
+			for x := 0; x < b.Width; x++ {
+				if r.Intn(6) == 0 {
+					black = !black
+				}
+				b.Set(x, y, black)
+			}
+		}
+		t.Logf("DecodeRuns() should return the %dx%d image given to EncodeRuns.", b.Width, b.Height)
+		decoded, err := DecodeRuns(EncodeRuns(b))
+		assert.NoError(t, err)
+		assert.Equal(t, b, decoded)
+	}
+}
+
+func TestRunsCompressForms(t *testing.T) {
+	t.Log("EncodeRuns() should make a form much smaller than its P4 image.")
+	b := form(1700, 2200, 1)
+	runs := EncodeRuns(b)
+	assert.Less(t, len(runs)*10, len(Encode(b)))
+
+	decoded, err := DecodeRuns(runs)
+	assert.NoError(t, err)
+	assert.Equal(t, b, decoded)
+}
+
+func TestDecodeRunsInvalid(t *testing.T) {
+	for _, input := range []string{"", "\x80", "\x02", "\x02\x01", "\x02\x01\x03", "\x02\x01\x01", "\x02\x01\x02\x00", "\x80\x80\x80\x80\x08\x80\x80\x80\x80\x08", "\x00\x80\x80\x80\x80\x04"} {
+		t.Logf("DecodeRuns(%q) should fail.", input)
+		_, err := DecodeRuns([]byte(input))
+		assert.True(t, errors.Is(err, stringManipulator.ErrMalformed), "got %v", err)
+	}
+
+	t.Log("DecodeRuns() should accept zero-width images up to maxEmptyHeight lines.")
+	b, err := DecodeRuns(EncodeRuns(New(0, maxEmptyHeight)))
+	assert.NoError(t, err)
+	assert.Equal(t, maxEmptyHeight, b.Height)
+}
+
+func BenchmarkEncodeRuns(b *testing.B) {
+	img := form(1700, 2200, 1)
+	b.SetBytes(int64(len(img.Pix)))
+	for i := 0; i < b.N; i++ {
+		EncodeRuns(img)
+	}
+}
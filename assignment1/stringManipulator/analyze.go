@@ -0,0 +1,162 @@
+package stringManipulator
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// LongestRunsReported is the number of runs listed in Report.LongestRuns
+const LongestRunsReported = 10
+
+// Risk rates how likely run-length encoding is to make an input larger or to lose data
+type Risk int
+
+const (
+	// RiskLow means a lossless mode makes the input smaller
+	RiskLow Risk = iota
+	// RiskMedium means every lossless mode makes the input larger
+	RiskMedium
+	// RiskHigh means the input has digits but no repeats, so Compress cannot unpack it and every lossless mode grows it
+	RiskHigh
+)
+
+// String returns the name of the risk
+func (r Risk) String() string {
+	switch r {
+	case RiskLow:
+		return "low"
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	}
+	return "Risk(" + strconv.Itoa(int(r)) + ")"
+}
+
+// MarshalText encodes the risk as its name
+func (r Risk) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalText decodes a risk from its name
+func (r *Risk) UnmarshalText(text []byte) error {
+	for _, risk := range []Risk{RiskLow, RiskMedium, RiskHigh} {
+		if string(text) == risk.String() {
+			*r = risk
+			return nil
+		}
+	}
+	return fmt.Errorf("stringManipulator: unknown risk %q", text)
+}
+
+// Run is a maximal sequence of one repeated character
+type Run struct {
+	Char   string `json:"char"`
+	Offset int    `json:"offset"` // byte offset of the first repetition
+	Length int    `json:"length"` // number of repetitions
+}
+
+// Bucket counts the runs of one length
+type Bucket struct {
+	Length int `json:"length"`
+	Count  int `json:"count"`
+}
+
+// Estimate is the size of the input encoded in one mode
+type Estimate struct {
+	Mode     string  `json:"mode"`
+	Size     int     `json:"size"`
+	Ratio    float64 `json:"ratio"`    // size divided by the input length, 0 for empty input
+	Lossless bool    `json:"lossless"` // whether the mode unpacks to the input
+}
+
+// Report describes how well an input compresses with run-length encoding
+type Report struct {
+	Length      int        `json:"length"` // input length in bytes
+	Chars       int        `json:"chars"`  // characters, counting each invalid UTF-8 byte as one
+	Digits      int        `json:"digits"` // characters that are ASCII digits
+	Runs        int        `json:"runs"`   // maximal runs of one character
+	Histogram   []Bucket   `json:"histogram"`
+	LongestRuns []Run      `json:"longest_runs"`
+	Estimates   []Estimate `json:"estimates"` // one per mode, smallest first
+	Risk        Risk       `json:"risk"`
+}
+
+// analyzedModes are the option sets estimated by Analyze in addition to the registered codecs
+var analyzedModes = []struct {
+	name string
+	opts Options
+}{
+	{"legacy", Options{}},
+	{"escaped", Options{Format: FormatEscaped}},
+	{"escaped grapheme", Options{Format: FormatEscaped, Unit: UnitGrapheme}},
+	{"delimited", Options{CountEncoding: Delimited}},
+	{"varint", Options{CountEncoding: Varint}},
+}
+
+// Analyze measures the runs in s and the size of s in every mode and registered codec
+func Analyze(s string) Report {
+	r := Report{Length: len(s), Histogram: []Bucket{}, LongestRuns: []Run{}}
+
+	histogram := map[int]int{}
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		char := s[i : i+size]
+		run := Run{Char: char, Offset: i, Length: 1}
+		for i += size; strings.HasPrefix(s[i:], char) && runeLen(s[i:]) == size; i += size {
+			run.Length++
+		}
+
+		r.Chars += run.Length
+		if size == 1 && isDigit(rune(char[0])) {
+			r.Digits += run.Length
+		}
+		r.Runs++
+		histogram[run.Length]++
+		r.LongestRuns = append(r.LongestRuns, run)
+	}
+
+	for length, count := range histogram {
+		r.Histogram = append(r.Histogram, Bucket{length, count})
+	}
+	sort.Slice(r.Histogram, func(i, j int) bool { return r.Histogram[i].Length < r.Histogram[j].Length })
+	sort.SliceStable(r.LongestRuns, func(i, j int) bool { return r.LongestRuns[i].Length > r.LongestRuns[j].Length })
+	if len(r.LongestRuns) > LongestRunsReported {
+		r.LongestRuns = r.LongestRuns[:LongestRunsReported]
+	}
+
+	estimate := func(mode string, size int, lossless bool) {
+		e := Estimate{Mode: mode, Size: size, Lossless: lossless}
+		if len(s) > 0 {
+			e.Ratio = float64(size) / float64(len(s))
+		}
+		r.Estimates = append(r.Estimates, e)
+	}
+	shrinks := false
+	for _, m := range analyzedModes {
+		out, err := CompressWith(s, m.opts)
+		if err == nil {
+			lossless := m.opts.Format != FormatLegacy || m.opts.CountEncoding != Decimal || r.Digits == 0
+			shrinks = shrinks || lossless && len(out) < len(s)
+			estimate(m.name, len(out), lossless)
+		}
+	}
+	for _, c := range Codecs() {
+		out, err := c.Encode([]byte(s))
+		if err == nil {
+			estimate("codec "+c.Name(), len(out), true)
+		}
+	}
+	sort.SliceStable(r.Estimates, func(i, j int) bool { return r.Estimates[i].Size < r.Estimates[j].Size })
+
+	switch {
+	case r.Digits > 0 && r.Runs == r.Chars:
+		r.Risk = RiskHigh
+	case len(s) > 0 && !shrinks:
+		r.Risk = RiskMedium
+	}
+	return r
+}
@@ -0,0 +1,61 @@
+package stringManipulator
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Log("Analyze() should count every maximal run, including runs longer than Compress writes at once.")
+	input := "aaaaaaaaaaaabb€€€c\xff\xff1"
+	r := Analyze(input)
+	assert.Equal(t, 27, r.Length)
+	assert.Equal(t, 21, r.Chars)
+	assert.Equal(t, 1, r.Digits)
+	assert.Equal(t, 6, r.Runs)
+	assert.Equal(t, []Bucket{{1, 2}, {2, 2}, {3, 1}, {12, 1}}, r.Histogram)
+	assert.Equal(t, []Run{{"a", 0, 12}, {"€", 14, 3}, {"b", 12, 2}, {"\xff", 24, 2}, {"c", 23, 1}, {"1", 26, 1}}, r.LongestRuns)
+	assert.Equal(t, RiskLow, r.Risk)
+
+	t.Log("Analyze() should estimate every mode and codec, smallest first.")
+	modes := map[string]Estimate{}
+	for i, e := range r.Estimates {
+		modes[e.Mode] = e
+		if i > 0 {
+			assert.True(t, r.Estimates[i-1].Size <= e.Size)
+		}
+	}
+	assert.Len(t, modes, len(analyzedModes)+len(Codecs()))
+	assert.Equal(t, Estimate{"legacy", 14, 14.0 / 27, false}, modes["legacy"])
+	assert.True(t, modes["escaped"].Lossless)
+	assert.Contains(t, modes, "codec lz77")
+}
+
+func TestAnalyzeRisk(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Risk
+	}{
+		{"", RiskLow},
+		{"aaaabbbb", RiskLow},
+		{"abcdef", RiskMedium},
+		{"a1b2c3", RiskHigh},
+		{"aaaaaaaaaa1b2c3", RiskLow},
+	}
+	for _, test := range tests {
+		t.Logf("Analyze(%q) should rate the expansion risk %v.", test.input, test.expected)
+		assert.Equal(t, test.expected, Analyze(test.input).Risk)
+	}
+}
+
+func TestAnalyzeJSON(t *testing.T) {
+	t.Log("A Report should marshal the risk by name and long runs by their first offset.")
+	b, err := json.Marshal(Analyze(strings.Repeat("x", 20)))
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"risk":"low"`)
+	assert.Contains(t, string(b), `"longest_runs":[{"char":"x","offset":0,"length":20}]`)
+	assert.Contains(t, string(b), `"histogram":[{"length":20,"count":1}]`)
+}
@@ -0,0 +1,171 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"encoding/ascii85"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+)
+
+// Armor selects a text-safe encoding for binary output, such as that of Varint or the codecs,
+// so that it can be carried in JSON strings, environment variables and URLs.
+// Every armor starts with a prefix, which Dearmor uses to detect it.
+type Armor int
+
+const (
+	// ArmorNone leaves the data as it is
+	ArmorNone Armor = iota
+	// ArmorBase64URL writes Base64URLPrefix and the data in unpadded URL-safe base64 (RFC 4648)
+	ArmorBase64URL
+	// ArmorAscii85 writes the data in Ascii85 between Ascii85Prefix and Ascii85Suffix, as Adobe does.
+	// It is the most compact armor, but its output contains quotes and backslashes that JSON has to escape.
+	ArmorAscii85
+	// ArmorQuoted writes QuotedPrefix and the data with every byte other than an ASCII letter, a digit, '-', '.', '_' or '~'
+	// written as '=' and two upper-case hex digits, like quoted-printable. Mostly textual data stays readable.
+	ArmorQuoted
+)
+
+// Prefixes and suffixes written by the armors
+const (
+	Base64URLPrefix = "b64:"
+	Ascii85Prefix   = "<~"
+	Ascii85Suffix   = "~>"
+	QuotedPrefix    = "qp:"
+)
+
+// quotedEscape starts a byte written as hex by ArmorQuoted
+const quotedEscape = '='
+
+// trailingSpace is removed from the end of armored data by Dearmor, as shells and editors add a final newline
+const trailingSpace = " \t\r\n"
+
+// String returns the name of the armor
+func (a Armor) String() string {
+	switch a {
+	case ArmorNone:
+		return "none"
+	case ArmorBase64URL:
+		return "base64url"
+	case ArmorAscii85:
+		return "ascii85"
+	case ArmorQuoted:
+		return "quoted"
+	}
+	return "Armor(" + strconv.Itoa(int(a)) + ")"
+}
+
+// Encode returns data in armor a. It returns ErrInvalidOptions for an unknown armor.
+func (a Armor) Encode(data []byte) ([]byte, error) {
+	switch a {
+	case ArmorNone:
+		return data, nil
+	case ArmorBase64URL:
+		dst := make([]byte, len(Base64URLPrefix)+base64.RawURLEncoding.EncodedLen(len(data)))
+		copy(dst, Base64URLPrefix)
+		base64.RawURLEncoding.Encode(dst[len(Base64URLPrefix):], data)
+		return dst, nil
+	case ArmorAscii85:
+		dst := make([]byte, len(Ascii85Prefix)+ascii85.MaxEncodedLen(len(data))+len(Ascii85Suffix))
+		copy(dst, Ascii85Prefix)
+		n := ascii85.Encode(dst[len(Ascii85Prefix):], data)
+		dst = append(dst[:len(Ascii85Prefix)+n], Ascii85Suffix...)
+		return dst, nil
+	case ArmorQuoted:
+		const hex = "0123456789ABCDEF"
+		dst := make([]byte, 0, len(QuotedPrefix)+len(data)*3/2)
+		dst = append(dst, QuotedPrefix...)
+		for _, c := range data {
+			if quotedSafe(c) {
+				dst = append(dst, c)
+			} else {
+				dst = append(dst, quotedEscape, hex[c>>4], hex[c&0xf])
+			}
+		}
+		return dst, nil
+	}
+	return nil, ErrInvalidOptions
+}
+
+// quotedSafe reports whether ArmorQuoted writes c as it is: the unreserved characters of URLs (RFC 3986)
+func quotedSafe(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || isDigit(rune(c)) || c == '-' || c == '.' || c == '_' || c == '~'
+}
+
+// DetectArmor returns the armor data starts with, or ArmorNone.
+// Only the prefix is checked, so a few bytes from the start of a stream are enough.
+func DetectArmor(data []byte) Armor {
+	switch {
+	case bytes.HasPrefix(data, []byte(Base64URLPrefix)):
+		return ArmorBase64URL
+	case bytes.HasPrefix(data, []byte(Ascii85Prefix)):
+		return ArmorAscii85
+	case bytes.HasPrefix(data, []byte(QuotedPrefix)):
+		return ArmorQuoted
+	}
+	return ArmorNone
+}
+
+// Dearmor detects the armor of data with DetectArmor and removes it.
+// Data without a known prefix is returned unchanged with ArmorNone, so unarmored output that happens to start
+// with a prefix is misread; armor such output when it may be passed to Dearmor.
+// Trailing ASCII whitespace after armored data is ignored. The returned error wraps ErrMalformed.
+func Dearmor(data []byte) ([]byte, Armor, error) {
+	a := DetectArmor(data)
+	if a != ArmorNone {
+		data = bytes.TrimRight(data, trailingSpace)
+	}
+	switch a {
+	case ArmorBase64URL:
+		// Padding is optional, as other encoders add it
+		src := bytes.TrimRight(data[len(Base64URLPrefix):], "=")
+		dst := make([]byte, base64.RawURLEncoding.DecodedLen(len(src)))
+		n, err := base64.RawURLEncoding.Decode(dst, src)
+		if err != nil {
+			return nil, a, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return dst[:n], a, nil
+	case ArmorAscii85:
+		src := data[len(Ascii85Prefix):]
+		if !bytes.HasSuffix(src, []byte(Ascii85Suffix)) {
+			return nil, a, fmt.Errorf("%w: Ascii85 data does not end with %s", ErrMalformed, Ascii85Suffix)
+		}
+		src = src[:len(src)-len(Ascii85Suffix)]
+		// 'z' stands for four bytes, so the output may be four times longer than the input
+		dst := make([]byte, 4*len(src))
+		n, _, err := ascii85.Decode(dst, src, true)
+		if err != nil {
+			return nil, a, fmt.Errorf("%w: %v", ErrMalformed, err)
+		}
+		return dst[:n], a, nil
+	case ArmorQuoted:
+		src := data[len(QuotedPrefix):]
+		dst := make([]byte, 0, len(src))
+		for i := 0; i < len(src); i++ {
+			c := src[i]
+			if c == quotedEscape {
+				if i+2 >= len(src) || !isHexDigit(src[i+1]) || !isHexDigit(src[i+2]) {
+					return nil, a, fmt.Errorf("%w: invalid quoted byte at offset %d", ErrMalformed, len(QuotedPrefix)+i)
+				}
+				c = unhex(src[i+1])<<4 | unhex(src[i+2])
+				i += 2
+			} else if !quotedSafe(c) {
+				return nil, a, fmt.Errorf("%w: unquoted byte %q at offset %d", ErrMalformed, c, len(QuotedPrefix)+i)
+			}
+			dst = append(dst, c)
+		}
+		return dst, a, nil
+	}
+	return data, ArmorNone, nil
+}
+
+// unhex returns the value of the hex digit c
+func unhex(c byte) byte {
+	switch {
+	case c <= '9':
+		return c - '0'
+	case c >= 'a':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 10
+}
@@ -0,0 +1,84 @@
+package stringManipulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var armors = []Armor{ArmorNone, ArmorBase64URL, ArmorAscii85, ArmorQuoted}
+
+func TestArmorEncode(t *testing.T) {
+	tests := []struct {
+		armor    Armor
+		input    string
+		expected string
+	}{
+		{ArmorNone, "\\1a4", "\\1a4"},
+		{ArmorBase64URL, "", "b64:"},
+		{ArmorBase64URL, "\xfb\xff", "b64:-_8"},
+		{ArmorAscii85, "", "<~~>"},
+		{ArmorAscii85, "\x00\x00\x00\x00a", "<~z@/~>"},
+		{ArmorQuoted, "", "qp:"},
+		{ArmorQuoted, "\\1a4 b~", "qp:=5C1a4=20b~"},
+		{ArmorQuoted, "=\xff", "qp:=3D=FF"},
+	}
+	for _, test := range tests {
+		t.Logf("%v.Encode(%q) should return %q.", test.armor, test.input, test.expected)
+		out, err := test.armor.Encode([]byte(test.input))
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(out))
+	}
+
+	t.Log("Encode() should reject an unknown armor.")
+	_, err := Armor(-1).Encode(nil)
+	assert.Equal(t, ErrInvalidOptions, err)
+}
+
+func TestArmorRoundTrip(t *testing.T) {
+	inputs := append(codecInputs, randomBytes(1000), []byte("b64:<~qp:"))
+	for _, a := range armors[1:] {
+		for _, input := range inputs {
+			t.Logf("Dearmor() should detect %v and return the original %d bytes.", a, len(input))
+			armored, err := a.Encode(input)
+			assert.NoError(t, err)
+			for _, c := range armored {
+				assert.True(t, c > ' ' && c < 0x7f, "%v wrote %q", a, c)
+			}
+			out, detected, err := Dearmor(armored)
+			assert.NoError(t, err)
+			assert.Equal(t, a, detected)
+			assert.Equal(t, string(input), string(out))
+		}
+	}
+}
+
+func TestDearmor(t *testing.T) {
+	t.Log("Dearmor() should return unarmored data unchanged.")
+	out, a, err := Dearmor([]byte("a4b"))
+	assert.NoError(t, err)
+	assert.Equal(t, ArmorNone, a)
+	assert.Equal(t, "a4b", string(out))
+
+	t.Log("Dearmor() should accept padded base64 and lower-case hex.")
+	out, _, err = Dearmor([]byte("b64:-_8="))
+	assert.NoError(t, err)
+	assert.Equal(t, "\xfb\xff", string(out))
+	out, _, err = Dearmor([]byte("qp:=3d=ff"))
+	assert.NoError(t, err)
+	assert.Equal(t, "=\xff", string(out))
+
+	for _, input := range []string{"b64:aGk\n", "<~BP@~>\r\n", "qp:hi \t\n"} {
+		t.Logf("Dearmor(%q) should ignore the trailing whitespace.", input)
+		out, _, err = Dearmor([]byte(input))
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", string(out))
+	}
+
+	for _, input := range []string{"b64:a", "b64:+/", "<~z", "<~ab{~>", "qp:=", "qp:=4", "qp:=4g", "qp:a b"} {
+		t.Logf("Dearmor(%q) should fail.", input)
+		_, _, err := Dearmor([]byte(input))
+		assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+	}
+}
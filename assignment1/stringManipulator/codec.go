@@ -0,0 +1,175 @@
+package stringManipulator
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Codec is a compression algorithm over byte slices.
+// Decode(Encode(src)) must return src for every input.
+type Codec interface {
+	Name() string                      // Name() returns the unique name used to select the codec
+	ID() byte                          // ID() returns the unique identifier stored alongside encoded data
+	Encode(src []byte) ([]byte, error) // Encode() compresses src
+	Decode(src []byte) ([]byte, error) // Decode() reverses Encode
+}
+
+// LimitedDecoder is implemented by codecs that can give up as soon as the output grows longer than a limit,
+// instead of decoding everything first. Open and UnpackParallel use it with the length recorded for the data.
+type LimitedDecoder interface {
+	DecodeLimit(src []byte, limit int) ([]byte, error) // DecodeLimit() is Decode, failing once the output exceeds limit bytes
+}
+
+// decodeLimit decodes src with c and returns ErrLength when the output is longer than limit.
+// Codecs without DecodeLimit are decoded in full first, which is fine for those whose output is bounded by their input.
+func decodeLimit(c Codec, src []byte, limit int) ([]byte, error) {
+	var data []byte
+	var err error
+	if l, ok := c.(LimitedDecoder); ok {
+		data, err = l.DecodeLimit(src, limit)
+	} else {
+		data, err = c.Decode(src)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > limit {
+		return nil, ErrLength
+	}
+	return data, nil
+}
+
+// IDs of the codecs provided by this package
+const (
+	RLEID      byte = 1
+	PackBitsID byte = 2
+	HuffmanID  byte = 3
+	LZ77ID     byte = 4
+)
+
+// Codecs provided by this package. They are registered when the package is initialised.
+var (
+	RLE      Codec = rleCodec{}
+	PackBits Codec = packBitsCodec{}
+	Huffman  Codec = huffmanCodec{}
+	LZ77     Codec = lz77Codec{}
+)
+
+// ErrDuplicateCodec is returned when registering a codec whose name or ID is already taken
+var ErrDuplicateCodec = errors.New("stringManipulator: codec name or ID already registered")
+
+// registry holds the codecs available by name and ID
+var registry = struct {
+	sync.RWMutex
+	byName map[string]Codec
+	byID   map[byte]Codec
+}{
+	byName: make(map[string]Codec),
+	byID:   make(map[byte]Codec),
+}
+
+func init() {
+	for _, c := range []Codec{RLE, PackBits, Huffman, LZ77} {
+		if err := Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Register makes a codec available to CodecByName, CodecByID and Best
+func Register(c Codec) error {
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, ok := registry.byName[c.Name()]; ok {
+		return ErrDuplicateCodec
+	}
+	if _, ok := registry.byID[c.ID()]; ok {
+		return ErrDuplicateCodec
+	}
+	registry.byName[c.Name()] = c
+	registry.byID[c.ID()] = c
+	return nil
+}
+
+// CodecByName returns the registered codec with the given name
+func CodecByName(name string) (Codec, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.byName[name]
+	return c, ok
+}
+
+// CodecByID returns the registered codec with the given ID
+func CodecByID(id byte) (Codec, bool) {
+	registry.RLock()
+	defer registry.RUnlock()
+	c, ok := registry.byID[id]
+	return c, ok
+}
+
+// Codecs returns every registered codec ordered by ID
+func Codecs() []Codec {
+	registry.RLock()
+	defer registry.RUnlock()
+	codecs := make([]Codec, 0, len(registry.byID))
+	for _, c := range registry.byID {
+		codecs = append(codecs, c)
+	}
+	sort.Slice(codecs, func(i, j int) bool { return codecs[i].ID() < codecs[j].ID() })
+	return codecs
+}
+
+// Best encodes src with every registered codec and returns the codec giving the smallest output, along with that output.
+// Ties go to the codec with the lowest ID.
+func Best(src []byte) (Codec, []byte, error) {
+	var best Codec
+	var bestOut []byte
+	for _, c := range Codecs() {
+		out, err := c.Encode(src)
+		if err != nil {
+			return nil, nil, err
+		}
+		if best == nil || len(out) < len(bestOut) {
+			best, bestOut = c, out
+		}
+	}
+	return best, bestOut, nil
+}
+
+// rleCodec encodes with the escaped format, which round-trips any byte sequence
+type rleCodec struct{}
+
+func (rleCodec) Name() string { return "rle" }
+func (rleCodec) ID() byte     { return RLEID }
+
+func (rleCodec) Encode(src []byte) ([]byte, error) {
+	s, err := CompressWith(string(src), Options{Format: FormatEscaped})
+	return []byte(s), err
+}
+
+func (rleCodec) Decode(src []byte) ([]byte, error) {
+	s, err := UnpackWith(string(src), Options{Format: FormatEscaped})
+	return []byte(s), err
+}
+
+func (rleCodec) DecodeLimit(src []byte, limit int) ([]byte, error) {
+	// The limit is the length recorded, and checksummed, with the data, so it replaces the default
+	// to let containers hold more than DefaultMaxOutput. A MaxOutput of 0 selects the default,
+	// and decodeLimit rejects anything longer than 0 anyway.
+	if limit == 0 {
+		limit = 1
+	}
+	s, err := UnpackWith(string(src), Options{Format: FormatEscaped, MaxOutput: limit})
+	return []byte(s), err
+}
+
+// packBitsCodec wraps CompressBytes and UnpackBytes
+type packBitsCodec struct{}
+
+func (packBitsCodec) Name() string { return "packbits" }
+func (packBitsCodec) ID() byte     { return PackBitsID }
+
+func (packBitsCodec) Encode(src []byte) ([]byte, error) { return CompressBytes(src), nil }
+func (packBitsCodec) Decode(src []byte) ([]byte, error) { return UnpackBytes(src) }
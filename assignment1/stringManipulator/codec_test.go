@@ -0,0 +1,124 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// codecInputs are inputs every codec must round-trip
+var codecInputs = [][]byte{
+	{},
+	{0x00},
+	[]byte("aaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj"),
+	[]byte("11111111111111111111"),
+	[]byte(strings.Repeat("the quick brown fox jumps over the lazy dog. ", 50)),
+	bytes.Repeat([]byte{0xff, 0x00, 0xfe}, 300),
+	[]byte("\xc3\xc3\xc3\x28\xe2\x82\\\\\\9"),
+	randomBytes(5000),
+}
+
+// randomBytes returns n pseudo-random bytes that are the same on every run
+func randomBytes(n int) []byte {
+	b := make([]byte, n)
+	rand.New(rand.NewSource(1)).Read(b)
+	return b
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	for _, c := range Codecs() {
+		for _, input := range codecInputs {
+			t.Logf("%s: Decode(Encode()) should return the %d byte input.", c.Name(), len(input))
+			encoded, err := c.Encode(input)
+			assert.NoError(t, err)
+			result, err := c.Decode(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, input, result)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	t.Log("The built-in codecs should be available by name and ID.")
+	names := []string{}
+	for _, c := range Codecs() {
+		names = append(names, c.Name())
+		byName, ok := CodecByName(c.Name())
+		assert.True(t, ok)
+		assert.Equal(t, c, byName)
+		byID, ok := CodecByID(c.ID())
+		assert.True(t, ok)
+		assert.Equal(t, c, byID)
+	}
+	assert.Equal(t, []string{"rle", "packbits", "huffman", "lz77"}, names)
+
+	_, ok := CodecByName("zip")
+	assert.False(t, ok)
+	assert.Equal(t, ErrDuplicateCodec, Register(RLE))
+}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		input    []byte
+		expected Codec
+	}{
+		{[]byte(strings.Repeat("a", 1000)), RLE},
+		{bytes.Repeat([]byte{0x00, 0xff, 0x00}, 1), PackBits},
+		{[]byte(strings.Repeat("abcdefgh", 500)), LZ77},
+		{[]byte(strings.Repeat("a", 90) + strings.Repeat("b", 9) + "cbabcbacbbacbabcbbacbbbcacbabcbabcab"), Huffman},
+	}
+	for _, test := range tests {
+		t.Logf("Best() should choose %s for the input.", test.expected.Name())
+		c, encoded, err := Best(test.input)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected.Name(), c.Name())
+		decoded, err := c.Decode(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, test.input, decoded)
+	}
+}
+
+func TestHuffmanLongCodes(t *testing.T) {
+	t.Log("huffmanLengths() should limit code lengths for skewed distributions.")
+	var freq [256]int
+	a, b := 1, 1
+	for i := 0; i < 45; i++ {
+		freq[i] = a
+		a, b = b, a+b
+	}
+	symbols := huffmanLengths(freq)
+	assert.Len(t, symbols, 45)
+
+	kraft := 0.0
+	for _, s := range symbols {
+		assert.True(t, s.len <= maxHuffmanLen)
+		kraft += 1 / float64(uint64(1)<<uint(s.len))
+	}
+	assert.Equal(t, 1.0, kraft)
+}
+
+func TestCodecsDecodeFails(t *testing.T) {
+	tests := []struct {
+		codec Codec
+		input []byte
+	}{
+		{Huffman, []byte{}},
+		{Huffman, []byte{0x05, 0x01, 'a'}},
+		{Huffman, []byte{0x05, 0x02, 'a', 0x01, 'b', 0x00, 0xff}},
+		{Huffman, []byte{0x09, 0x01, 'a', 0x01, 0x00}},
+		{LZ77, []byte{0x05, 0x02, 'a', 'b', 0x00, 0x05}},
+		{LZ77, []byte{0x05, 0x05, 'a'}},
+		{LZ77, []byte{0x03, 0x01, 'a'}},
+		{RLE, []byte(`\1\`)},
+		{PackBits, []byte{0x05}},
+	}
+	for _, test := range tests {
+		t.Logf("%s: Decode() should return an error when the input is % X", test.codec.Name(), test.input)
+		_, err := test.codec.Decode(test.input)
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+}
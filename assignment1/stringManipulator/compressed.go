@@ -0,0 +1,209 @@
+package stringManipulator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Compressed is an immutable string held as runs, answering queries by character position without unpacking.
+// Positions count characters as Unpack does: runes, with every invalid UTF-8 byte a character of its own.
+// Adjacent runs of one character are merged, so a run may be longer than Compress writes at once.
+type Compressed struct {
+	runs []compressedRun
+	ends []int // ends[k] is the position just past runs[k]; the rune-offset index searched by every query
+}
+
+// compressedRun is count repetitions of char
+type compressedRun struct {
+	char  string
+	count int
+}
+
+// NewCompressed indexes s, the output of Compress. Any string is accepted and is read the way Unpack reads it.
+func NewCompressed(s string) Compressed {
+	var c Compressed
+	var prev string
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		char := s[i : i+size]
+		i += size
+		if size == 1 && isDigit(rune(char[0])) && prev != "" {
+			c.appendRun(prev, int(char[0]-'0'))
+			prev = ""
+		} else {
+			c.appendRun(prev, 1)
+			prev = char
+		}
+	}
+	c.appendRun(prev, 1)
+	return c
+}
+
+// appendRun adds count repetitions of char, extending the last run when it repeats the same character
+func (c *Compressed) appendRun(char string, count int) {
+	if char == "" || count == 0 {
+		return
+	}
+	end := count
+	if n := len(c.runs); n > 0 {
+		end += c.ends[n-1]
+		if c.runs[n-1].char == char {
+			c.runs[n-1].count += count
+			c.ends[n-1] = end
+			return
+		}
+	}
+	c.runs = append(c.runs, compressedRun{char, count})
+	c.ends = append(c.ends, end)
+}
+
+// Len returns the number of characters in the unpacked string
+func (c Compressed) Len() int {
+	if len(c.ends) == 0 {
+		return 0
+	}
+	return c.ends[len(c.ends)-1]
+}
+
+// Runs returns the number of runs after merging
+func (c Compressed) Runs() int {
+	return len(c.runs)
+}
+
+// find returns the index of the run holding position i
+func (c Compressed) find(i int) int {
+	return sort.Search(len(c.ends), func(k int) bool { return c.ends[k] > i })
+}
+
+// At returns the character at position i, or utf8.RuneError for an invalid UTF-8 byte.
+// It panics if i is out of range.
+func (c Compressed) At(i int) rune {
+	if i < 0 || i >= c.Len() {
+		panic(fmt.Sprintf("stringManipulator: index %d out of range [0:%d]", i, c.Len()))
+	}
+	r, _ := utf8.DecodeRuneInString(c.runs[c.find(i)].char)
+	return r
+}
+
+// Slice returns the unpacked characters from position i up to but not including j.
+// It panics if the positions are out of range.
+func (c Compressed) Slice(i, j int) string {
+	if i < 0 || j < i || j > c.Len() {
+		panic(fmt.Sprintf("stringManipulator: slice bounds [%d:%d] out of range [0:%d]", i, j, c.Len()))
+	}
+
+	var b strings.Builder
+	for k := c.find(i); i < j; k++ {
+		n := c.ends[k] - i
+		if j < c.ends[k] {
+			n = j - i
+		}
+		b.WriteString(strings.Repeat(c.runs[k].char, n))
+		i += n
+	}
+	return b.String()
+}
+
+// String returns the value in the form written by Compress
+func (c Compressed) String() string {
+	var b strings.Builder
+	for _, run := range c.runs {
+		for count := run.count; count > 0; count -= maxLegacyRun {
+			b.WriteString(run.char)
+			if n := min(count, maxLegacyRun); n > 1 {
+				b.WriteByte(byte('0' + n))
+			}
+		}
+	}
+	return b.String()
+}
+
+// Unpack returns the whole unpacked string
+func (c Compressed) Unpack() string {
+	return c.Slice(0, c.Len())
+}
+
+// Concat returns c followed by d, merging the run at the boundary when both sides repeat the same character
+func (c Compressed) Concat(d Compressed) Compressed {
+	joined := Compressed{
+		runs: make([]compressedRun, len(c.runs), len(c.runs)+len(d.runs)),
+		ends: make([]int, len(c.ends), len(c.ends)+len(d.ends)),
+	}
+	copy(joined.runs, c.runs)
+	copy(joined.ends, c.ends)
+	for _, run := range d.runs {
+		joined.appendRun(run.char, run.count)
+	}
+	return joined
+}
+
+// Equal reports whether c and d unpack to the same characters.
+// Runs are always merged, so this compares runs rather than unpacked strings.
+func (c Compressed) Equal(d Compressed) bool {
+	if len(c.runs) != len(d.runs) {
+		return false
+	}
+	for k := range c.runs {
+		if c.runs[k] != d.runs[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// IndexRune returns the position of the first instance of r, or -1 if r is not present.
+// utf8.RuneError matches invalid UTF-8 bytes as well as U+FFFD.
+func (c Compressed) IndexRune(r rune) int {
+	for k, run := range c.runs {
+		if first, _ := utf8.DecodeRuneInString(run.char); first == r {
+			return c.ends[k] - run.count
+		}
+	}
+	return -1
+}
+
+// Index returns the position of the first instance of the plain string sub, or -1 if sub is not present.
+// sub is split into runs and matched run by run, so the cost depends on the number of runs rather than the unpacked length.
+func (c Compressed) Index(sub string) int {
+	var p Compressed
+	for i := 0; i < len(sub); {
+		size := runeLen(sub[i:])
+		p.appendRun(sub[i:i+size], 1)
+		i += size
+	}
+
+	m := len(p.runs)
+	switch m {
+	case 0:
+		return 0
+	case 1:
+		// A single run matches inside any run of the same character that is long enough
+		for k, run := range c.runs {
+			if run.char == p.runs[0].char && run.count >= p.runs[0].count {
+				return c.ends[k] - run.count
+			}
+		}
+		return -1
+	}
+
+	// Otherwise the first run of sub must end a run of c, the last must start one and every run between must match exactly
+	for k := 0; k+m <= len(c.runs); k++ {
+		first, last := p.runs[0], p.runs[m-1]
+		if c.runs[k].char != first.char || c.runs[k].count < first.count {
+			continue
+		}
+		if c.runs[k+m-1].char != last.char || c.runs[k+m-1].count < last.count {
+			continue
+		}
+		match := true
+		for q := 1; q < m-1 && match; q++ {
+			match = c.runs[k+q] == p.runs[q]
+		}
+		if match {
+			return c.ends[k] - first.count
+		}
+	}
+	return -1
+}
@@ -0,0 +1,159 @@
+package stringManipulator
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressed(t *testing.T) {
+	input := "aaaaaaaaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj"
+	c := NewCompressed(Compress(input))
+
+	t.Log("NewCompressed() should merge the runs Compress splits at nine.")
+	assert.Equal(t, utf8.RuneCountInString(input), c.Len())
+	assert.Equal(t, 10, c.Runs())
+	assert.Equal(t, Compress(input), c.String())
+	assert.Equal(t, input, c.Unpack())
+
+	t.Log("At() should return the character at every position.")
+	for i, r := range []rune(input) {
+		assert.Equal(t, r, c.At(i))
+	}
+
+	t.Log("Slice() should return the characters between two positions.")
+	runes := []rune(input)
+	for i := 0; i <= len(runes); i++ {
+		for j := i; j <= len(runes); j++ {
+			assert.Equal(t, string(runes[i:j]), c.Slice(i, j))
+		}
+	}
+}
+
+func TestCompressedUnpack(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "€", "😀", "1", "0", "\xff"}
+	for i := 0; i < 200; i++ {
+		s := randomText(r, alphabet, 30, 12)
+		t.Logf("NewCompressed(%q) should read its input the way Unpack does.", s)
+		c := NewCompressed(s)
+		assert.Equal(t, Unpack(s), c.Unpack())
+		assert.Equal(t, Compress(Unpack(s)), c.String())
+		if utf8.ValidString(s) {
+			assert.Equal(t, utf8.RuneCountInString(Unpack(s)), c.Len())
+		}
+	}
+}
+
+func TestCompressedEmpty(t *testing.T) {
+	t.Log("An empty Compressed should have no characters.")
+	var zero Compressed
+	for _, c := range []Compressed{zero, NewCompressed(""), NewCompressed("a0")} {
+		assert.Equal(t, 0, c.Len())
+		assert.Equal(t, "", c.Slice(0, 0))
+		assert.Equal(t, "", c.String())
+	}
+}
+
+func TestCompressedInvalidUTF8(t *testing.T) {
+	t.Log("At() should return utf8.RuneError for an invalid byte, while Slice() keeps the byte.")
+	c := NewCompressed("\xff3a")
+	assert.Equal(t, 4, c.Len())
+	assert.Equal(t, utf8.RuneError, c.At(2))
+	assert.Equal(t, "\xff\xffa", c.Slice(1, 4))
+}
+
+func TestCompressedOutOfRange(t *testing.T) {
+	c := NewCompressed("a3")
+	t.Log("At() and Slice() should panic on positions out of range.")
+	assert.Panics(t, func() { c.At(3) })
+	assert.Panics(t, func() { c.At(-1) })
+	assert.Panics(t, func() { c.Slice(2, 1) })
+	assert.Panics(t, func() { c.Slice(0, 4) })
+}
+
+func BenchmarkCompressedAt(b *testing.B) {
+	c := NewCompressed(Compress(benchmarkInputs[0].input))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c.At(i % c.Len())
+	}
+}
+
+func TestCompressedConcat(t *testing.T) {
+	t.Log("Concat() should merge the run at the boundary.")
+	c := NewCompressed("a2b5").Concat(NewCompressed("b7c"))
+	assert.Equal(t, "aabbbbbbbbbbbbc", c.Unpack())
+	assert.Equal(t, 3, c.Runs())
+	assert.Equal(t, "a2b9b3c", c.String())
+
+	t.Log("Concat() should leave both operands unchanged.")
+	a := NewCompressed("a2b5")
+	a.Concat(NewCompressed("b"))
+	assert.Equal(t, "aabbbbb", a.Unpack())
+
+	var empty Compressed
+	assert.True(t, a.Equal(a.Concat(empty)))
+	assert.True(t, a.Equal(empty.Concat(a)))
+}
+
+func TestCompressedEqual(t *testing.T) {
+	t.Log("Equal() should compare the unpacked characters, however the runs were split.")
+	assert.True(t, NewCompressed("a9a3").Equal(NewCompressed("a5a7")))
+	assert.True(t, NewCompressed("ab0").Equal(NewCompressed("a")))
+	assert.False(t, NewCompressed("a9a3").Equal(NewCompressed("a9a2")))
+	assert.False(t, NewCompressed("ab").Equal(NewCompressed("ba")))
+}
+
+func TestCompressedIndex(t *testing.T) {
+	c := NewCompressed("a9a3€5bc3\xff2")
+	tests := []struct {
+		sub      string
+		expected int
+	}{
+		{"", 0},
+		{"a", 0},
+		{"aaaaaaaaaaaa", 0},
+		{"aaaaaaaaaaaaa", -1},
+		{"€€", 12},
+		{"a€", 11},
+		{"aa€€€€€bc", 10},
+		{"€bcc", 16},
+		{"bccc\xff", 17},
+		{"cc\xff\xff", 19},
+		{"bcccc", -1},
+		{"d", -1},
+	}
+	for _, test := range tests {
+		t.Logf("Index(%q) should return %d.", test.sub, test.expected)
+		assert.Equal(t, test.expected, c.Index(test.sub))
+	}
+
+	t.Log("IndexRune() should return the position of the first instance of a rune.")
+	assert.Equal(t, 12, c.IndexRune('€'))
+	assert.Equal(t, 18, c.IndexRune('c'))
+	assert.Equal(t, 21, c.IndexRune(utf8.RuneError))
+	assert.Equal(t, -1, c.IndexRune('z'))
+}
+
+func TestCompressedOperationsMatchStrings(t *testing.T) {
+	t.Log("Concat(), Equal() and Index() should agree with the same operations on unpacked strings.")
+	r := rand.New(rand.NewSource(2))
+	alphabet := []string{"a", "b", "€", "😀"}
+	for i := 0; i < 500; i++ {
+		x, y := randomText(r, alphabet, 20, 6), randomText(r, alphabet, 20, 6)
+		cx, cy := NewCompressed(Compress(x)), NewCompressed(Compress(y))
+		assert.Equal(t, x+y, cx.Concat(cy).Unpack())
+		assert.Equal(t, x == y, cx.Equal(cy))
+
+		sub := randomText(r, alphabet, 4, 3)
+		expected := strings.Index(x, sub)
+		if expected >= 0 {
+			expected = utf8.RuneCountInString(x[:expected])
+		}
+		assert.Equal(t, expected, cx.Index(sub), "Index(%q) in %q", sub, x)
+	}
+}
@@ -0,0 +1,127 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+)
+
+// Container layout, all integers big-endian:
+//
+//	magic    4 bytes  ContainerMagic
+//	version  1 byte   ContainerVersion
+//	codec    1 byte   ID of the codec that produced the payload
+//	length   8 bytes  length of the original data
+//	checksum 4 bytes  CRC-32 (IEEE) of the preceding header fields and the payload
+//	payload  the encoded data
+const (
+	ContainerMagic      = "\x89RLE"
+	ContainerVersion    = 1
+	containerHeaderSize = len(ContainerMagic) + 1 + 1 + 8 + 4
+)
+
+var (
+	// ErrNotContainer is returned when data does not start with ContainerMagic
+	ErrNotContainer = errors.New("stringManipulator: not a container")
+	// ErrTruncated is returned when a container is shorter than its header
+	ErrTruncated = errors.New("stringManipulator: container is truncated")
+	// ErrUnsupportedVersion is returned when a container was written by a newer format version
+	ErrUnsupportedVersion = errors.New("stringManipulator: unsupported container version")
+	// ErrUnknownCodec is returned when a container names a codec that is not registered
+	ErrUnknownCodec = errors.New("stringManipulator: unknown codec")
+	// ErrChecksum is returned when a container's checksum does not match its contents
+	ErrChecksum = errors.New("stringManipulator: container checksum mismatch")
+	// ErrLength is returned when the decoded payload does not have the length recorded in the header
+	ErrLength = errors.New("stringManipulator: decoded length does not match container header")
+)
+
+// Header is the metadata stored at the start of a container
+type Header struct {
+	Version  byte   // container format version
+	CodecID  byte   // ID of the codec that produced the payload
+	Length   uint64 // length of the original data
+	Checksum uint32 // CRC-32 of the header fields and the payload
+}
+
+// Seal encodes data with c and wraps the result in a container recording the codec, the original length and a checksum
+func Seal(c Codec, data []byte) ([]byte, error) {
+	payload, err := c.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+	return SealPayload(c, len(data), payload), nil
+}
+
+// SealPayload wraps payload, the output of c for length bytes of data, in a container as Seal does.
+// It saves encoding the data again when the payload is already at hand, such as after Best.
+func SealPayload(c Codec, length int, payload []byte) []byte {
+	blob := make([]byte, containerHeaderSize, containerHeaderSize+len(payload))
+	copy(blob, ContainerMagic)
+	blob[4] = ContainerVersion
+	blob[5] = c.ID()
+	binary.BigEndian.PutUint64(blob[6:14], uint64(length))
+	blob = append(blob, payload...)
+
+	crc := crc32.NewIEEE()
+	crc.Write(blob[:14])
+	crc.Write(payload)
+	binary.BigEndian.PutUint32(blob[14:18], crc.Sum32())
+	return blob
+}
+
+// ParseHeader reads the header of a container without checking the checksum
+func ParseHeader(blob []byte) (Header, error) {
+	if !bytes.HasPrefix(blob, []byte(ContainerMagic)) {
+		if len(blob) < len(ContainerMagic) && bytes.HasPrefix([]byte(ContainerMagic), blob) {
+			return Header{}, ErrTruncated
+		}
+		return Header{}, ErrNotContainer
+	}
+	if len(blob) < containerHeaderSize {
+		return Header{}, ErrTruncated
+	}
+	return Header{
+		Version:  blob[4],
+		CodecID:  blob[5],
+		Length:   binary.BigEndian.Uint64(blob[6:14]),
+		Checksum: binary.BigEndian.Uint32(blob[14:18]),
+	}, nil
+}
+
+// Open validates the magic, version, codec and checksum of a container, then decodes and returns the original data
+func Open(blob []byte) ([]byte, error) {
+	h, err := ParseHeader(blob)
+	if err != nil {
+		return nil, err
+	}
+	if h.Version != ContainerVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	c, ok := CodecByID(h.CodecID)
+	if !ok {
+		return nil, ErrUnknownCodec
+	}
+
+	payload := blob[containerHeaderSize:]
+	crc := crc32.NewIEEE()
+	crc.Write(blob[:14])
+	crc.Write(payload)
+	if crc.Sum32() != h.Checksum {
+		return nil, ErrChecksum
+	}
+
+	// The header length bounds the output, so a small corrupt payload cannot expand without limit
+	limit := maxInt
+	if h.Length < uint64(maxInt) {
+		limit = int(h.Length)
+	}
+	data, err := decodeLimit(c, payload, limit)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(data)) != h.Length {
+		return nil, ErrLength
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSealOpen(t *testing.T) {
+	for _, c := range Codecs() {
+		for _, input := range codecInputs {
+			t.Logf("%s: Open(Seal()) should return the %d byte input.", c.Name(), len(input))
+			blob, err := Seal(c, input)
+			assert.NoError(t, err)
+
+			h, err := ParseHeader(blob)
+			assert.NoError(t, err)
+			assert.Equal(t, byte(ContainerVersion), h.Version)
+			assert.Equal(t, c.ID(), h.CodecID)
+			assert.Equal(t, uint64(len(input)), h.Length)
+
+			result, err := Open(blob)
+			assert.NoError(t, err)
+			assert.Equal(t, input, result)
+		}
+	}
+}
+
+func TestSealLayout(t *testing.T) {
+	t.Log("Seal() should write the magic, version, codec ID, length and checksum before the payload.")
+	blob, err := Seal(RLE, []byte("aaaa"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\x89RLE\x01\x01\x00\x00\x00\x00\x00\x00\x00\x04\x93\x41\xb2\x76\\1a4"), blob)
+
+	t.Log("SealPayload() should write what Seal() does for the codec's output.")
+	assert.Equal(t, blob, SealPayload(RLE, 4, []byte("\\1a4")))
+}
+
+func TestOpenFails(t *testing.T) {
+	blob, err := Seal(LZ77, []byte(strings.Repeat("abc", 100)))
+	assert.NoError(t, err)
+	corrupt := func(i int, b byte) []byte {
+		c := append([]byte(nil), blob...)
+		c[i] = b
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected error
+	}{
+		{"not a container", []byte("aaaa"), ErrNotContainer},
+		{"truncated magic", []byte("\x89R"), ErrTruncated},
+		{"truncated header", blob[:10], ErrTruncated},
+		{"newer version", corrupt(4, 2), ErrUnsupportedVersion},
+		{"unknown codec", corrupt(5, 0xee), ErrUnknownCodec},
+		{"wrong length", corrupt(13, 0), ErrChecksum},
+		{"corrupt payload", corrupt(len(blob)-1, blob[len(blob)-1]^1), ErrChecksum},
+		{"truncated payload", blob[:len(blob)-1], ErrChecksum},
+	}
+	for _, test := range tests {
+		t.Logf("Open() should return %v when the container has a %s.", test.expected, test.name)
+		_, err := Open(test.input)
+		assert.Equal(t, test.expected, err)
+	}
+}
+
+func TestOpenLimitsOutput(t *testing.T) {
+	t.Log("Open() should stop decoding once the payload expands past the length in the header.")
+	payload := []byte("\\1a100000000")
+	blob := make([]byte, containerHeaderSize, containerHeaderSize+len(payload))
+	copy(blob, ContainerMagic)
+	blob[4] = ContainerVersion
+	blob[5] = RLEID
+	binary.BigEndian.PutUint64(blob[6:14], 4)
+	blob = append(blob, payload...)
+	binary.BigEndian.PutUint32(blob[14:18], crc32.ChecksumIEEE(append(blob[:14:14], payload...)))
+
+	_, err := Open(blob)
+	assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+
+	for _, c := range []Codec{Huffman, LZ77} {
+		t.Logf("%s: Open() should reject a payload recording a longer length than the header.", c.Name())
+		sealed, err := Seal(c, []byte(strings.Repeat("abc", 100)))
+		assert.NoError(t, err)
+		binary.BigEndian.PutUint64(sealed[6:14], 10)
+		crc := crc32.NewIEEE()
+		crc.Write(sealed[:14])
+		crc.Write(sealed[containerHeaderSize:])
+		binary.BigEndian.PutUint32(sealed[14:18], crc.Sum32())
+		_, err = Open(sealed)
+		assert.Equal(t, ErrLength, err)
+	}
+}
+
+func TestSealOpenLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decodes more than DefaultMaxOutput bytes")
+	}
+	t.Log("Open(Seal(RLE)) should return input longer than DefaultMaxOutput, whose length the header records.")
+	input := bytes.Repeat([]byte("a"), DefaultMaxOutput+1)
+	blob, err := Seal(RLE, input)
+	assert.NoError(t, err)
+	result, err := Open(blob)
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(input, result), "got %d bytes", len(result))
+}
@@ -0,0 +1,207 @@
+package stringManipulator
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Delta format: the differences between consecutive values, the first value being its difference from zero,
+// with runs of equal differences collapsed. Each run is written as
+//
+//	delta  varint (zigzag), the difference, wrapping around on overflow
+//	count  uvarint, the number of values in the run, at least 1
+//
+// so a counter or a series of evenly spaced timestamps takes a few bytes whatever its length.
+// The format works on integers rather than bytes, so it is not a Codec.
+
+// EncodeDeltas returns values in the delta format
+func EncodeDeltas(values []int64) []byte {
+	var dst []byte
+	var prev int64
+	var delta, count uint64
+	for _, v := range values {
+		d := uint64(v) - uint64(prev)
+		prev = v
+		if count > 0 && d == delta {
+			count++
+			continue
+		}
+		dst = appendDeltaRun(dst, delta, count)
+		delta, count = d, 1
+	}
+	return appendDeltaRun(dst, delta, count)
+}
+
+// appendDeltaRun appends a run of count values count apart by delta, or nothing when count is zero
+func appendDeltaRun(dst []byte, delta, count uint64) []byte {
+	if count == 0 {
+		return dst
+	}
+	var varint [binary.MaxVarintLen64]byte
+	dst = append(dst, varint[:binary.PutVarint(varint[:], int64(delta))]...)
+	return append(dst, varint[:binary.PutUvarint(varint[:], count)]...)
+}
+
+// MaxDeltaValues is the largest number of values DecodeDeltas returns, as much memory as DefaultMaxOutput.
+// A run of a few bytes can hold any count, so DecodeDeltas fails rather than allocating without bound.
+// DeltaDecoder holds a single run in memory and has no limit.
+const MaxDeltaValues = DefaultMaxOutput / 8
+
+// DecodeDeltas reverses EncodeDeltas. The returned error wraps ErrMalformed, including for more than MaxDeltaValues values.
+func DecodeDeltas(data []byte) ([]int64, error) {
+	var values []int64
+	var prev int64
+	for i := 0; i < len(data); {
+		delta, count, n, err := readDeltaRun(data[i:], i)
+		if err != nil {
+			return nil, err
+		}
+		if count > uint64(MaxDeltaValues-len(values)) {
+			return nil, fmt.Errorf("%w: run at offset %d exceeds the limit of %d values", ErrMalformed, i, MaxDeltaValues)
+		}
+		i += n
+		for ; count > 0; count-- {
+			prev += delta
+			values = append(values, prev)
+		}
+	}
+	return values, nil
+}
+
+// readDeltaRun reads the run at the start of data, found at offset in the whole input.
+// It returns the run and its length in bytes.
+func readDeltaRun(data []byte, offset int) (delta int64, count uint64, n int, err error) {
+	delta, k := binary.Varint(data)
+	if k <= 0 {
+		return 0, 0, 0, fmt.Errorf("%w: invalid delta at offset %d", ErrMalformed, offset)
+	}
+	count, j := binary.Uvarint(data[k:])
+	if j <= 0 || count == 0 {
+		return 0, 0, 0, fmt.Errorf("%w: invalid count at offset %d", ErrMalformed, offset+k)
+	}
+	return delta, count, k + j, nil
+}
+
+// DeltaEncoder writes values in the delta format to an underlying io.Writer as they arrive.
+// The output is identical to EncodeDeltas of all the values written, and only the current run is held in memory.
+type DeltaEncoder struct {
+	w      *bufio.Writer
+	buf    []byte
+	prev   int64
+	delta  uint64
+	count  uint64
+	closed bool
+	err    error
+}
+
+// NewDeltaEncoder returns a DeltaEncoder writing to w. Close must be called to flush the final run.
+func NewDeltaEncoder(w io.Writer) *DeltaEncoder {
+	return &DeltaEncoder{w: bufio.NewWriter(w), buf: make([]byte, 0, 2*binary.MaxVarintLen64)}
+}
+
+// Write encodes values, which continue the sequence of the previous calls
+func (e *DeltaEncoder) Write(values ...int64) error {
+	if e.closed {
+		return ErrClosed
+	}
+	for _, v := range values {
+		if e.err != nil {
+			break
+		}
+		d := uint64(v) - uint64(e.prev)
+		e.prev = v
+		if e.count > 0 && d == e.delta {
+			e.count++
+			continue
+		}
+		e.flushRun()
+		e.delta, e.count = d, 1
+	}
+	return e.err
+}
+
+// Close flushes the final run to the underlying writer. It does not close the underlying writer.
+func (e *DeltaEncoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+	e.flushRun()
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.err
+}
+
+// flushRun writes the current run to the underlying writer
+func (e *DeltaEncoder) flushRun() {
+	if e.err != nil {
+		return
+	}
+	e.buf = appendDeltaRun(e.buf[:0], e.delta, e.count)
+	_, e.err = e.w.Write(e.buf)
+	e.count = 0
+}
+
+// DeltaDecoder reads values in the delta format from an underlying io.Reader.
+// Long runs are expanded as they are read, so memory use does not depend on the length of the sequence.
+type DeltaDecoder struct {
+	r      *bufio.Reader
+	offset int // bytes consumed so far, for error messages
+	prev   int64
+	delta  int64
+	count  uint64 // values left in the current run
+	err    error
+}
+
+// NewDeltaDecoder returns a DeltaDecoder reading from r
+func NewDeltaDecoder(r io.Reader) *DeltaDecoder {
+	return &DeltaDecoder{r: bufio.NewReader(r)}
+}
+
+// Read decodes up to len(values) values into values. It returns io.EOF after the last value.
+// Input that ends inside a run returns an error wrapping ErrMalformed.
+func (d *DeltaDecoder) Read(values []int64) (int, error) {
+	n := 0
+	for n < len(values) {
+		if d.count == 0 {
+			if d.err != nil {
+				break
+			}
+			d.next()
+			continue
+		}
+		d.prev += d.delta
+		values[n] = d.prev
+		d.count--
+		n++
+	}
+	if n == 0 && d.err != nil {
+		return 0, d.err
+	}
+	return n, nil
+}
+
+// next reads the following run header
+func (d *DeltaDecoder) next() {
+	// A run header is at most two varints, so a Peek of that size holds a whole one unless input ends first
+	b, err := d.r.Peek(2 * binary.MaxVarintLen64)
+	if len(b) == 0 {
+		d.err = err
+		return
+	}
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		d.err = err
+		return
+	}
+	delta, count, size, err := readDeltaRun(b, d.offset)
+	if err != nil {
+		d.err = err
+		return
+	}
+	d.r.Discard(size)
+	d.offset += size
+	d.delta, d.count = delta, count
+}
@@ -0,0 +1,166 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var deltaInputs = [][]int64{
+	nil,
+	{0},
+	{-1},
+	{1, 2, 3, 4, 5},
+	{1600000000, 1600000010, 1600000020, 1600000030, 1600000031, 1600000041},
+	{5, 5, 5, 5, 4, 3, 2},
+	{math.MaxInt64, math.MinInt64, math.MaxInt64, 0, math.MinInt64},
+}
+
+func TestEncodeDeltas(t *testing.T) {
+	tests := []struct {
+		values   []int64
+		expected []byte
+	}{
+		{nil, nil},
+		{[]int64{7}, []byte{14, 1}},
+		{[]int64{1, 2, 3, 4, 5}, []byte{2, 5}},
+		{[]int64{10, 20, 30, 29}, []byte{20, 3, 1, 1}},
+		{[]int64{5, 5, 5}, []byte{10, 1, 0, 2}},
+	}
+	for _, test := range tests {
+		t.Logf("EncodeDeltas(%v) should return %v.", test.values, test.expected)
+		assert.Equal(t, test.expected, EncodeDeltas(test.values))
+	}
+}
+
+func TestDeltasRoundTrip(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	inputs := deltaInputs
+	for i := 0; i < 100; i++ {
+		values := make([]int64, r.Intn(50))
+		for j := range values {
+			if j > 0 && r.Intn(3) > 0 {
+				values[j] = values[j-1] + int64(r.Intn(3))
+			} else {
+				values[j] = r.Int63() - r.Int63()
+			}
+		}
+		inputs = append(inputs, values)
+	}
+
+	for _, values := range inputs {
+		t.Logf("DecodeDeltas() should return the %d values given to EncodeDeltas.", len(values))
+		decoded, err := DecodeDeltas(EncodeDeltas(values))
+		assert.NoError(t, err)
+		assert.Equal(t, len(values), len(decoded))
+		if len(values) > 0 {
+			assert.Equal(t, values, decoded)
+		}
+
+		t.Log("DeltaEncoder and DeltaDecoder should match EncodeDeltas and DecodeDeltas whatever the batch size.")
+		var buf bytes.Buffer
+		enc := NewDeltaEncoder(&buf)
+		for i := 0; i < len(values); i += 3 {
+			assert.NoError(t, enc.Write(values[i:min(i+3, len(values))]...))
+		}
+		assert.NoError(t, enc.Close())
+		assert.Equal(t, EncodeDeltas(values), buf.Bytes())
+
+		dec := NewDeltaDecoder(&buf)
+		var streamed []int64
+		batch := make([]int64, 2)
+		for {
+			n, err := dec.Read(batch)
+			streamed = append(streamed, batch[:n]...)
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, decoded, streamed)
+	}
+}
+
+func TestDeltasCompressTimeSeries(t *testing.T) {
+	t.Log("EncodeDeltas() should make a time series far smaller than run-length encoding its text.")
+	r := rand.New(rand.NewSource(2))
+	values := make([]int64, 10000)
+	text := make([]string, len(values))
+	ts := int64(1600000000000)
+	for i := range values {
+		ts += 1000
+		if r.Intn(50) == 0 {
+			ts += int64(r.Intn(5))
+		}
+		values[i] = ts
+		text[i] = strconv.FormatInt(ts, 10)
+	}
+	packed := EncodeDeltas(values)
+	textRLE, err := CompressWith(strings.Join(text, "\n"), Options{Format: FormatEscaped})
+	assert.NoError(t, err)
+	assert.Less(t, len(packed)*50, len(textRLE))
+}
+
+func TestDecodeDeltasInvalid(t *testing.T) {
+	for _, input := range []string{"\x02", "\x02\x00", "\x80", "\x02\x80", "\x02\x01\x04", "\xff\xff\xff\xff\xff\xff\xff\xff\xff\xff\x01"} {
+		t.Logf("DecodeDeltas(%q) and DeltaDecoder should fail.", input)
+		_, err := DecodeDeltas([]byte(input))
+		assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+
+		dec := NewDeltaDecoder(strings.NewReader(input))
+		for err == nil || err != io.EOF && !errors.Is(err, ErrMalformed) {
+			_, err = dec.Read(make([]int64, 4))
+		}
+		assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+	}
+}
+
+func TestDecodeDeltasLimit(t *testing.T) {
+	t.Log("DecodeDeltas() should reject a 10 byte input asking for 2^63 values.")
+	_, err := DecodeDeltas([]byte("\x02\xff\xff\xff\xff\xff\xff\xff\xff\x7f"))
+	assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+
+	t.Log("DecodeDeltas() should count the limit over every run.")
+	data := appendDeltaRun(appendDeltaRun(nil, 1, 1), 2, MaxDeltaValues)
+	_, err = DecodeDeltas(data)
+	assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+	values, err := DecodeDeltas(appendDeltaRun(nil, 1, 1000))
+	assert.NoError(t, err)
+	assert.Len(t, values, 1000)
+}
+
+func TestDeltaDecoderLongRun(t *testing.T) {
+	t.Log("DeltaDecoder should expand a run of a billion values lazily.")
+	dec := NewDeltaDecoder(bytes.NewReader(appendDeltaRun(nil, 2, 1e9)))
+	values := make([]int64, 3)
+	n, err := dec.Read(values)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []int64{2, 4, 6}, values)
+}
+
+func TestDeltaEncoderWriteAfterClose(t *testing.T) {
+	t.Log("DeltaEncoder.Write() should fail after Close().")
+	enc := NewDeltaEncoder(ioutil.Discard)
+	assert.NoError(t, enc.Close())
+	assert.Equal(t, ErrClosed, enc.Write(1))
+}
+
+func BenchmarkEncodeDeltas(b *testing.B) {
+	values := make([]int64, 1<<16)
+	for i := range values {
+		values[i] = int64(i) * 1000
+	}
+	b.SetBytes(int64(8 * len(values)))
+	for i := 0; i < b.N; i++ {
+		EncodeDeltas(values)
+	}
+}
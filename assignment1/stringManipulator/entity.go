@@ -0,0 +1,85 @@
+package stringManipulator
+
+// EntityEscape is the escape rune used with UnitEntity when none is given, since DefaultEscape starts escape sequences
+const EntityEscape = '~'
+
+// entityLen returns the byte length of the first unit of s with UnitEntity:
+// a character reference, a backslash escape sequence or else a single rune
+func entityLen(s string) int {
+	if n := referenceLen(s); n > 0 {
+		return n
+	}
+	return runeLen(s)
+}
+
+// referenceLen returns the byte length of the character reference or escape sequence at the start of s, or 0 if there is none.
+// References are "&name;", "&#digits;" and "&#xhex;". Escape sequences are a backslash followed by one of 0abfnrtv\'"
+// or by x and 2, u and 4 or U and 8 hexadecimal digits.
+// Every reference ends at a fixed point, so what follows it never changes its length.
+func referenceLen(s string) int {
+	if len(s) < 2 {
+		return 0
+	}
+
+	if s[0] == '\\' {
+		digits := 0
+		switch s[1] {
+		case '0', 'a', 'b', 'f', 'n', 'r', 't', 'v', '\\', '\'', '"':
+			return 2
+		case 'x':
+			digits = 2
+		case 'u':
+			digits = 4
+		case 'U':
+			digits = 8
+		default:
+			return 0
+		}
+		if len(s) < 2+digits || spanOf(s[2:2+digits], isHexDigit) != digits {
+			return 0
+		}
+		return 2 + digits
+	}
+
+	if s[0] != '&' {
+		return 0
+	}
+	var n int
+	switch {
+	case isLetter(s[1]):
+		n = 2 + spanOf(s[2:], func(c byte) bool { return isLetter(c) || isDigit(rune(c)) })
+	case s[1] == '#' && len(s) > 2 && (s[2] == 'x' || s[2] == 'X'):
+		if n = 3 + spanOf(s[3:], isHexDigit); n == 3 {
+			return 0
+		}
+	case s[1] == '#':
+		if n = 2 + spanOf(s[2:], func(c byte) bool { return isDigit(rune(c)) }); n == 2 {
+			return 0
+		}
+	default:
+		return 0
+	}
+	if n == len(s) || s[n] != ';' {
+		return 0
+	}
+	return n + 1
+}
+
+// spanOf returns the length of the prefix of s made of bytes satisfying f
+func spanOf(s string, f func(byte) bool) int {
+	n := 0
+	for n < len(s) && f(s[n]) {
+		n++
+	}
+	return n
+}
+
+// isLetter reports whether c is an ASCII letter
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// isHexDigit reports whether c is an ASCII hexadecimal digit
+func isHexDigit(c byte) bool {
+	return isDigit(rune(c)) || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,92 @@
+package stringManipulator
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntityLen(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{"&#39;&#39;", 5},
+		{"&#x1F600;", 9},
+		{"&amp;a", 5},
+		{"&frac12;", 8},
+		{"&amp", 1},
+		{"&#;", 1},
+		{"&#x;", 1},
+		{"&#12a;", 1},
+		{"& amp;", 1},
+		{"&1;", 1},
+		{`\n\n`, 2},
+		{`\\`, 2},
+		{`\'`, 2},
+		{`\x41`, 4},
+		{`\x4`, 1},
+		{`\u00e9`, 6},
+		{`\u00g9`, 1},
+		{`\U0001F600`, 10},
+		{`\q`, 1},
+		{`\`, 1},
+		{"€", 3},
+		{"\xff", 1},
+	}
+	for _, test := range tests {
+		t.Logf("entityLen(%q) should return %d.", test.input, test.expected)
+		assert.Equal(t, test.expected, entityLen(test.input))
+	}
+}
+
+var entityTests = []struct {
+	name     string
+	input    string
+	opts     Options
+	expected string
+}{
+	{"escaped", "&#39;&#39;&#39;11", Options{Format: FormatEscaped, Unit: UnitEntity}, "~1&#39;3~12"},
+	{"escaped", "&&&#;~~", Options{Format: FormatEscaped, Unit: UnitEntity}, "~1~&3#;~~2"},
+	{"escaped", `\\\\\q`, Options{Format: FormatEscaped, Unit: UnitEntity}, `~1\\2~\q`},
+	{"escaped", "&##;", Options{Format: FormatEscaped, Unit: UnitEntity}, "~1~&#2;"},
+	{"delimited", "&#39;&#39;&#39;", Options{CountEncoding: Delimited, Unit: UnitEntity}, "&#39;{3}"},
+	{"delimited", "&amp;&amp;x&lt;", Options{CountEncoding: Delimited, Unit: UnitEntity}, "&amp;{2}x&lt;"},
+	{"delimited", `\n\n\n\t`, Options{CountEncoding: Delimited, Unit: UnitEntity}, `\n{3}\t`},
+	{"delimited", `\x41\x41\u00e9\u00e9\u00e9`, Options{CountEncoding: Delimited, Unit: UnitEntity}, `\x41{2}\u00e9{3}`},
+	{"delimited", "&##;&#35;&#35;", Options{CountEncoding: Delimited, Unit: UnitEntity}, "&#{2};&#35;{2}"},
+	{"varint", "&#39;&#39;", Options{CountEncoding: Varint, Unit: UnitEntity}, "\x05&#39;\x02"},
+}
+
+func TestCompressEntities(t *testing.T) {
+	for _, test := range entityTests {
+		t.Logf("CompressWith(%q) should keep references whole in the %s format.", test.input, test.name)
+		result, err := CompressWith(test.input, test.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+}
+
+func TestUnpackEntities(t *testing.T) {
+	for _, test := range entityTests {
+		t.Logf("UnpackWith(%q) should repeat whole references in the %s format.", test.expected, test.name)
+		result, err := UnpackWith(test.expected, test.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, test.input, result)
+	}
+}
+
+func TestEntityEscape(t *testing.T) {
+	t.Log("UnitEntity should reject escapes that start references.")
+	for _, escape := range []rune{'\\', '&'} {
+		_, err := CompressWith("a", Options{Format: FormatEscaped, Unit: UnitEntity, Escape: escape})
+		assert.Equal(t, ErrInvalidEscape, err)
+	}
+
+	t.Log("UnitEntity should reject the legacy format with decimal counts, which cannot keep \"&##;\" apart from \"&#2;\".")
+	_, err := CompressWith("&##;", Options{Unit: UnitEntity})
+	assert.Equal(t, ErrInvalidOptions, err)
+	_, err = UnpackWith("&#2;", Options{Unit: UnitEntity})
+	assert.Equal(t, ErrInvalidOptions, err)
+	assert.Equal(t, "entity", UnitEntity.String())
+}
@@ -0,0 +1,127 @@
+package stringManipulator
+
+import (
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Format identifies an encoding written by this package
+type Format int
+
+const (
+	// FormatLegacy is the format written by Compress. Digits in the input cannot be told apart from counts.
+	FormatLegacy Format = iota
+	// FormatEscaped prefixes literal digits and escape runes with an escape rune so that every input round-trips
+	FormatEscaped
+)
+
+// String returns the name of the format
+func (f Format) String() string {
+	switch f {
+	case FormatLegacy:
+		return "legacy"
+	case FormatEscaped:
+		return "escaped"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// Versions of the escaped format.
+// EscapedVersion is written when the runs unpack to the input exactly. EscapedVersionNormalized is written when
+// normalization or case folding changed the input, and is followed by a byte recording which of them did.
+const (
+	EscapedVersion           = 1
+	EscapedVersionNormalized = 2
+)
+
+// escapedHeaderMax is the longest header of the escaped format
+const escapedHeaderMax = utf8.UTFMax + 2
+
+// DefaultEscape is the escape rune used when none is given
+const DefaultEscape = '\\'
+
+var (
+	// ErrInvalidEscape is returned when the escape rune is a digit or not a valid rune
+	ErrInvalidEscape = errors.New("stringManipulator: invalid escape rune")
+	// ErrMalformed is returned when encoded input cannot be decoded
+	ErrMalformed = errors.New("stringManipulator: malformed input")
+)
+
+// CompressEscaped compresses s using the escaped format.
+// The output starts with a header made of the escape rune and the format version, followed by the runs.
+// Literal digits and escape runes in s are written with the escape rune in front of them, so counts are never ambiguous
+// and runs of any length are written with a single count.
+// An escape of 0 selects DefaultEscape.
+func CompressEscaped(s string, escape rune) (string, error) {
+	return CompressWith(s, Options{Format: FormatEscaped, Escape: escape})
+}
+
+// UnpackEscaped reverses CompressEscaped. The escape rune is read from the header.
+// The returned error is a *SyntaxError.
+func UnpackEscaped(s string) (string, error) {
+	return UnpackWith(s, Options{Format: FormatEscaped})
+}
+
+// EscapedHeader is the metadata at the start of the escaped format
+type EscapedHeader struct {
+	Escape    rune          // escape rune used in the runs
+	Version   int           // EscapedVersion or EscapedVersionNormalized
+	Normalize Normalization // normalization form that changed the input, NormNone if none did
+	CaseFold  bool          // whether case folding changed the input
+	Size      int           // length of the header in bytes
+}
+
+// Lossy reports whether the runs may unpack to something other than the original input
+func (h EscapedHeader) Lossy() bool {
+	return h.Normalize != NormNone || h.CaseFold
+}
+
+// ParseEscapedHeader reads the header of s, the output of CompressEscaped or CompressWith with FormatEscaped.
+// The returned error is a *SyntaxError.
+func ParseEscapedHeader(s string) (EscapedHeader, error) {
+	escape, size := utf8.DecodeRuneInString(s)
+	if !validEscape(escape) || size == len(s) {
+		return EscapedHeader{}, newSyntaxError(s, 0, "an escaped format header")
+	}
+	h := EscapedHeader{Escape: escape, Version: int(s[size] - '0'), Size: size + 1}
+	switch h.Version {
+	case EscapedVersion:
+		return h, nil
+	case EscapedVersionNormalized:
+	default:
+		return EscapedHeader{}, newSyntaxError(s, size, "a supported escaped format version")
+	}
+
+	flags := -1
+	if h.Size < len(s) {
+		flags = int(s[h.Size]) - flagBase
+	}
+	if flags < 1 || flags > flagNFC|flagNFKC|flagCaseFold {
+		return EscapedHeader{}, newSyntaxError(s, h.Size, "normalization flags")
+	}
+	switch flags &^ flagCaseFold {
+	case 0:
+	case flagNFC:
+		h.Normalize = NFC
+	case flagNFKC:
+		h.Normalize = NFKC
+	default:
+		return EscapedHeader{}, newSyntaxError(s, h.Size, "normalization flags")
+	}
+	h.CaseFold = flags&flagCaseFold != 0
+	h.Size++
+	return h, nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+// validEscape reports whether r can be used as an escape rune
+func validEscape(r rune) bool {
+	return r != utf8.RuneError && utf8.ValidRune(r) && !isDigit(r)
+}
+
+// isDigit reports whether r is one of the ASCII digits used for counts
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
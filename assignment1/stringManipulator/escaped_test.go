@@ -0,0 +1,98 @@
+package stringManipulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var escapedTests = [][]string{
+	{
+		"",
+		"",
+	},
+	{
+		"aaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj",
+		`\1a6€5c3d3a2 ef2gj12`,
+	},
+	{
+		"11111111111111111111",
+		`\1\120`,
+	},
+	{
+		"a99999999999999999999",
+		`\1a\920`,
+	},
+	{
+		"aaaaaaaaa999999999999999999",
+		`\1a9\918`,
+	},
+	{
+		`\\\n`,
+		`\1\\3n`,
+	},
+	{
+		"\n\n\n",
+		"\\1\n3",
+	},
+}
+
+func TestCompressEscaped(t *testing.T) {
+	for i := 0; i < len(escapedTests); i++ {
+		input, expectedResult := escapedTests[i][0], escapedTests[i][1]
+		t.Logf("CompressEscaped() should return %s when the input is %s", expectedResult, input)
+		result, err := CompressEscaped(input, 0)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestUnpackEscaped(t *testing.T) {
+	for i := 0; i < len(escapedTests); i++ {
+		input, expectedResult := escapedTests[i][1], escapedTests[i][0]
+		t.Logf("UnpackEscaped() should return %s when the input is %s", expectedResult, input)
+		result, err := UnpackEscaped(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestEscapedRoundTrip(t *testing.T) {
+	inputs := []string{"1", "a1b22c333", "#1##2", "€€€1€", "\xff\xff9\xe2\x82", "😀😀😀3"}
+	for _, escape := range []rune{0, '#', '€', '😀'} {
+		for _, input := range inputs {
+			t.Logf("UnpackEscaped(CompressEscaped()) should return %q when the escape is %q", input, escape)
+			compressed, err := CompressEscaped(input, escape)
+			assert.NoError(t, err)
+			result, err := UnpackEscaped(compressed)
+			assert.NoError(t, err)
+			assert.Equal(t, input, result)
+		}
+	}
+}
+
+func TestCompressEscapedInvalidEscape(t *testing.T) {
+	t.Log("CompressEscaped() should return ErrInvalidEscape when the escape is a digit.")
+	_, err := CompressEscaped("abc", '7')
+	assert.Equal(t, ErrInvalidEscape, err)
+}
+
+func TestUnpackEscapedFails(t *testing.T) {
+	inputs := []string{
+		"a3",     // no header
+		`\3a3`,   // unknown version
+		`\2a3`,   // normalized version without flags
+		`\2ia3`,  // unknown flags
+		`\2da3`,  // both NFC and NFKC
+		`\13`,    // count without a character
+		"\\1a\\", // escape at end of input
+		`\1a1`,   // count of one
+		`\1a03`,  // leading zero
+	}
+	for _, input := range inputs {
+		t.Logf("UnpackEscaped() should return an error when the input is %s", input)
+		_, err := UnpackEscaped(input)
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+}
@@ -0,0 +1,224 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// roundTripOptions are the option sets that must round-trip every input
+var roundTripOptions = []Options{
+	{Format: FormatEscaped},
+	{Format: FormatEscaped, Escape: '€', MaxRun: 3},
+	{Format: FormatEscaped, Unit: UnitGrapheme, Escape: '#'},
+	{CountEncoding: Delimited},
+	{Format: FormatEscaped, CountEncoding: Delimited, Unit: UnitGrapheme},
+	{CountEncoding: Varint},
+	{CountEncoding: Varint, Unit: UnitGrapheme, MaxRun: 2},
+	{Format: FormatEscaped, Unit: UnitEntity},
+	{Format: FormatEscaped, CountEncoding: Delimited, Unit: UnitEntity, Escape: '#'},
+	{CountEncoding: Delimited, Unit: UnitEntity},
+	{CountEncoding: Varint, Unit: UnitEntity},
+}
+
+// normalizedOptions change the input before compressing it, so the output decodes to s normalized
+var normalizedOptions = []Options{
+	{Format: FormatEscaped, Normalize: NFC},
+	{Format: FormatEscaped, Normalize: NFKC},
+	{Format: FormatEscaped, Normalize: NFKC, CaseFold: true, Escape: '€'},
+	{Format: FormatEscaped, CaseFold: true, CountEncoding: Varint},
+}
+
+// checkRoundTrip checks every round-trip and size property for s
+func checkRoundTrip(t *testing.T, s string) {
+	compressed := Compress(s)
+	if len(compressed) > CompressBound(len(s), Options{}) {
+		t.Fatalf("Compress(%q) = %q exceeds CompressBound", s, compressed)
+	}
+	if !strings.ContainsAny(s, "0123456789") && Unpack(compressed) != s {
+		t.Fatalf("Unpack(Compress(%q)) = %q", s, Unpack(compressed))
+	}
+
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	enc.Write([]byte(s))
+	enc.Close()
+	if buf.String() != compressed {
+		t.Fatalf("Encoder(%q) = %q, Compress() = %q", s, buf.String(), compressed)
+	}
+
+	for _, opts := range roundTripOptions {
+		compressed, err := CompressWith(s, opts)
+		if err != nil {
+			t.Fatalf("CompressWith(%q, %+v) returned %v", s, opts, err)
+		}
+		if len(compressed) > CompressBound(len(s), opts) {
+			t.Fatalf("CompressWith(%q, %+v) = %q exceeds CompressBound", s, opts, compressed)
+		}
+		result, err := UnpackWith(compressed, opts)
+		if err != nil || result != s {
+			t.Fatalf("UnpackWith(CompressWith(%q, %+v)) = %q, %v", s, opts, result, err)
+		}
+	}
+
+	for _, opts := range normalizedOptions {
+		compressed, err := CompressWith(s, opts)
+		if err != nil {
+			t.Fatalf("CompressWith(%q, %+v) returned %v", s, opts, err)
+		}
+		if len(compressed) > CompressBound(len(s), opts) {
+			t.Fatalf("CompressWith(%q, %+v) = %q exceeds CompressBound", s, opts, compressed)
+		}
+		if _, err := UnpackWith(compressed, opts); err != nil {
+			t.Fatalf("UnpackWith(CompressWith(%q, %+v)) returned %v", s, opts, err)
+		}
+	}
+
+	for _, c := range Codecs() {
+		blob, err := Seal(c, []byte(s))
+		if err != nil {
+			t.Fatalf("Seal(%s, %q) returned %v", c.Name(), s, err)
+		}
+		result, err := Open(blob)
+		if err != nil || string(result) != s {
+			t.Fatalf("Open(Seal(%s, %q)) = %q, %v", c.Name(), s, result, err)
+		}
+	}
+}
+
+// fuzzMaxOutput is the output limit checkDecoders decodes with, keeping the output of a single input small
+const fuzzMaxOutput = 1 << 16
+
+// checkDecoders runs every decoder on s, which must not panic whatever s contains
+func checkDecoders(t *testing.T, s string) {
+	unpacked := Unpack(s)
+	streamed, err := ioutil.ReadAll(NewDecoder(strings.NewReader(s)))
+	if err != nil || string(streamed) != unpacked {
+		t.Fatalf("Decoder(%q) = %q, %v, Unpack() = %q", s, streamed, err, unpacked)
+	}
+	if strict, err := UnpackStrict(s); err == nil && strict != unpacked {
+		t.Fatalf("UnpackStrict(%q) = %q, Unpack() = %q", s, strict, unpacked)
+	}
+
+	// A count of a few bytes can ask for any length, which must fail cleanly at the limit
+	for _, opts := range roundTripOptions {
+		opts.MaxOutput = fuzzMaxOutput
+		result, err := UnpackWith(s, opts)
+		var syntaxErr *SyntaxError
+		if err != nil && !errors.As(err, &syntaxErr) {
+			t.Fatalf("UnpackWith(%q, %+v) returned %v, not a SyntaxError", s, opts, err)
+		}
+		if len(result) > fuzzMaxOutput {
+			t.Fatalf("UnpackWith(%q, %+v) returned %d bytes, over the limit", s, opts, len(result))
+		}
+	}
+	for _, c := range Codecs() {
+		if _, err := decodeLimit(c, []byte(s), fuzzMaxOutput); err != nil && err != ErrLength && !errors.Is(err, ErrMalformed) {
+			t.Fatalf("%s: DecodeLimit(%q) returned %v", c.Name(), s, err)
+		}
+	}
+	for _, tr := range []Transform{BWT, MTF} {
+		tr.Decode([]byte(s))
+	}
+	Open([]byte(s))
+}
+
+// fuzzSeeds are edge cases seeded alongside the real-world corpus in testdata/fuzz
+var fuzzSeeds = []string{
+	"",
+	"2020-07-14T10:00:00Z ERROR ERROR ERROR connection reset\n\n\n",
+	"id,name,name,name\n1,,,\n2,,,\n",
+	"<p>It&#39;s &amp;&amp;&amp; done</p>",
+	"\\n\\n\\t\\\\\\u00e9",
+	"👨‍👩‍👧👨‍👩‍👧🇺🇸🇺🇸éé",
+	"11111111111111111111",
+	"a99999999999999999999",
+	"\\1a9223372036854775807",
+	"\x01a\x80\x80\x80\x80\x80\x80\x80\x80\x40",
+	"a{1000000000000}",
+	"\xff\xfe\x00\x00\xe2\x82",
+}
+
+func FuzzCompress(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		checkRoundTrip(t, s)
+	})
+}
+
+func FuzzUnpack(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+		f.Add(Compress(seed))
+		for _, opts := range roundTripOptions {
+			compressed, _ := CompressWith(seed, opts)
+			f.Add(compressed)
+		}
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		checkDecoders(t, s)
+	})
+}
+
+// propertyAlphabet makes generated strings repetitive, digit-heavy and full of multi-rune clusters
+var propertyAlphabet = []string{"a", "b", "1", "9", "\\", "#", "€", "{", "}", "́", "‍", "👨", "🇺", "\xff", "\xe2\x82", "\n",
+	"&", "&#39;", "&amp;", ";", "x", "~", "\\n", "\\x4", "\\u00e9", "\ufdfa", "\u0390"}
+
+// propertyString is a generated input for testing/quick
+type propertyString string
+
+// Generate implements quick.Generator
+func (propertyString) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(propertyString(randomText(r, propertyAlphabet, r.Intn(size+1), 15)))
+}
+
+func TestRoundTripProperty(t *testing.T) {
+	t.Log("Every encoding should round-trip generated inputs within CompressBound.")
+	property := func(s propertyString) bool {
+		checkRoundTrip(t, string(s))
+		return !t.Failed()
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(1))}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDecodersProperty(t *testing.T) {
+	t.Log("Decoders should never panic on arbitrary input.")
+	property := func(s propertyString) bool {
+		checkDecoders(t, string(s))
+		return !t.Failed()
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500, Rand: rand.New(rand.NewSource(2))}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCompressBound(t *testing.T) {
+	t.Log("CompressBound() should hold for worst-case inputs.")
+	worst := []string{
+		strings.Repeat("1{", 50),
+		strings.Repeat("a{", 50),
+		strings.Repeat("ab", 50),
+		strings.Repeat("€{", 50),
+		strings.Repeat("12", 50),
+		strings.Repeat("\ufdfa", 10),
+		strings.Repeat("\u0390", 10),
+	}
+	options := append(append([]Options{{}}, roundTripOptions...), normalizedOptions...)
+	for _, s := range worst {
+		for _, opts := range options {
+			compressed, err := CompressWith(s, opts)
+			if err != nil || len(compressed) > CompressBound(len(s), opts) {
+				t.Errorf("CompressWith(%q, %+v) = %d bytes, bound %d", s, opts, len(compressed), CompressBound(len(s), opts))
+			}
+		}
+	}
+}
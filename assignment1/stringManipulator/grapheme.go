@@ -0,0 +1,32 @@
+package stringManipulator
+
+import (
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// graphemeWindow is the number of bytes first searched for the end of a grapheme cluster
+const graphemeWindow = 64
+
+// graphemeLen returns the byte length of the first extended grapheme cluster of s.
+// Only a window of s is segmented, doubling in size until the cluster ends inside it,
+// so walking a string cluster by cluster stays linear.
+func graphemeLen(s string) int {
+	for window := graphemeWindow; ; window *= 2 {
+		end := len(s)
+		if window < end {
+			end = window
+			for end < len(s) && !utf8.RuneStart(s[end]) {
+				end++
+			}
+		}
+
+		g := uniseg.NewGraphemes(s[:end])
+		g.Next()
+		_, to := g.Positions()
+		if to < end || end == len(s) {
+			return to
+		}
+	}
+}
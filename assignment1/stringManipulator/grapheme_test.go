@@ -0,0 +1,105 @@
+package stringManipulator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var graphemeTests = [][]string{
+	{
+		"👨‍👩‍👧👨‍👩‍👧",
+		"\\1👨‍👩‍👧2",
+	},
+	{
+		"🇺🇸🇺🇸🇺🇸🇫🇷",
+		"\\1🇺🇸3🇫🇷",
+	},
+	{
+		"e\u0301e\u0301e\u0301e",
+		"\\1e\u03013e",
+	},
+	{
+		"1\u20e31\u20e31",
+		"\\1\\1\u20e32\\1",
+	},
+	{
+		"👍🏽👍🏽👍🏽👍🏽👍",
+		"\\1👍🏽4👍",
+	},
+}
+
+func TestCompressGrapheme(t *testing.T) {
+	opts := Options{Format: FormatEscaped, Unit: UnitGrapheme}
+	for i := 0; i < len(graphemeTests); i++ {
+		input, expectedResult := graphemeTests[i][0], graphemeTests[i][1]
+		t.Logf("CompressWith() should return %q when the input is %q", expectedResult, input)
+		result, err := CompressWith(input, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestUnpackGrapheme(t *testing.T) {
+	opts := Options{Format: FormatEscaped, Unit: UnitGrapheme}
+	for i := 0; i < len(graphemeTests); i++ {
+		input, expectedResult := graphemeTests[i][1], graphemeTests[i][0]
+		t.Logf("UnpackWith() should return %q when the input is %q", expectedResult, input)
+		result, err := UnpackWith(input, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestGraphemeRoundTrip(t *testing.T) {
+	inputs := []string{
+		"\n\n\u0301\u0301",
+		"👨‍👩‍👧#👨‍👩‍👧\\👨‍👩‍👧‍👦",
+		"\u0600\u06001\n\u0600",
+		"ᄀᄀ각각각",
+		strings.Repeat("a\u0308", 100) + strings.Repeat("\u0308", 100),
+		"\xff\xff\u0301\xe2\x82",
+	}
+	for _, format := range []Format{FormatLegacy, FormatEscaped} {
+		for _, countEncoding := range []CountEncoding{Decimal, Varint, Delimited} {
+			if format == FormatLegacy && countEncoding == Decimal {
+				continue // literal digits are ambiguous
+			}
+			opts := Options{Format: format, CountEncoding: countEncoding, Unit: UnitGrapheme, Escape: '#'}
+			for _, input := range inputs {
+				t.Logf("UnpackWith(CompressWith()) should return %q when the options are %+v", input, opts)
+				compressed, err := CompressWith(input, opts)
+				assert.NoError(t, err)
+				result, err := UnpackWith(compressed, opts)
+				assert.NoError(t, err)
+				assert.Equal(t, input, result)
+			}
+		}
+	}
+}
+
+func TestGraphemeLegacy(t *testing.T) {
+	t.Log("CompressWith() should keep clusters whole in the legacy format.")
+	opts := Options{Unit: UnitGrapheme}
+	result, err := CompressWith("🇺🇸🇺🇸e\u0301e\u0301e\u0301", opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "🇺🇸2e\u03013", result)
+
+	unpacked, err := UnpackWith(result, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, "🇺🇸🇺🇸e\u0301e\u0301e\u0301", unpacked)
+}
+
+func TestGraphemeInvalidEscape(t *testing.T) {
+	t.Log("CompressWith() should reject escapes that could join a grapheme cluster.")
+	_, err := CompressWith("a", Options{Format: FormatEscaped, Unit: UnitGrapheme, Escape: '😀'})
+	assert.Equal(t, ErrInvalidEscape, err)
+}
+
+func TestGraphemeLen(t *testing.T) {
+	t.Log("graphemeLen() should find clusters longer than the initial window.")
+	s := "a" + strings.Repeat("\u0301", 200) + "b"
+	assert.Equal(t, len(s)-1, graphemeLen(s))
+	assert.Equal(t, 0, graphemeLen(""))
+}
@@ -0,0 +1,236 @@
+package stringManipulator
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// maxHuffmanLen is the longest code the Huffman codec writes
+const maxHuffmanLen = 32
+
+// huffmanCodec is a canonical Huffman coder over bytes.
+// The output is the uvarint input length, the uvarint number of symbols,
+// a (symbol, code length) byte pair for each symbol and the codes packed most significant bit first.
+type huffmanCodec struct{}
+
+func (huffmanCodec) Name() string { return "huffman" }
+func (huffmanCodec) ID() byte     { return HuffmanID }
+
+// huffmanSymbol is a byte and the length of its code
+type huffmanSymbol struct {
+	value byte
+	len   int
+}
+
+func (huffmanCodec) Encode(src []byte) ([]byte, error) {
+	var varint [binary.MaxVarintLen64]byte
+	dst := append([]byte(nil), varint[:binary.PutUvarint(varint[:], uint64(len(src)))]...)
+	if len(src) == 0 {
+		return dst, nil
+	}
+
+	var freq [256]int
+	for _, b := range src {
+		freq[b]++
+	}
+	symbols := huffmanLengths(freq)
+	codes := canonicalCodes(symbols)
+
+	dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(len(symbols)))]...)
+	for _, s := range symbols {
+		dst = append(dst, s.value, byte(s.len))
+	}
+
+	var acc uint64
+	var bits uint
+	for _, b := range src {
+		code := codes[b]
+		acc = acc<<uint(code.len) | code.bits
+		bits += uint(code.len)
+		for bits >= 8 {
+			bits -= 8
+			dst = append(dst, byte(acc>>bits))
+		}
+	}
+	if bits > 0 {
+		dst = append(dst, byte(acc<<(8-bits)))
+	}
+	return dst, nil
+}
+
+func (h huffmanCodec) Decode(src []byte) ([]byte, error) {
+	return h.DecodeLimit(src, maxInt)
+}
+
+func (huffmanCodec) DecodeLimit(src []byte, limit int) ([]byte, error) {
+	n, k := binary.Uvarint(src)
+	if k <= 0 {
+		return nil, fmt.Errorf("%w: Huffman length is truncated", ErrMalformed)
+	}
+	if n > uint64(limit) {
+		return nil, ErrLength
+	}
+	src = src[k:]
+	if n == 0 {
+		return []byte{}, nil
+	}
+
+	count, k := binary.Uvarint(src)
+	if k <= 0 || count == 0 || count > 256 || uint64(len(src)-k) < 2*count {
+		return nil, fmt.Errorf("%w: Huffman table is truncated", ErrMalformed)
+	}
+	src = src[k:]
+	symbols := make([]huffmanSymbol, count)
+	var seen [256]bool
+	kraft := uint64(0)
+	for i := range symbols {
+		symbols[i] = huffmanSymbol{value: src[2*i], len: int(src[2*i+1])}
+		if seen[symbols[i].value] || symbols[i].len < 1 || symbols[i].len > maxHuffmanLen {
+			return nil, fmt.Errorf("%w: Huffman table is invalid", ErrMalformed)
+		}
+		seen[symbols[i].value] = true
+		kraft += 1 << uint(maxHuffmanLen-symbols[i].len)
+	}
+	if kraft > 1<<maxHuffmanLen {
+		return nil, fmt.Errorf("%w: Huffman table is invalid", ErrMalformed)
+	}
+	src = src[2*count:]
+	if n > uint64(len(src))*8 {
+		return nil, fmt.Errorf("%w: Huffman data is truncated", ErrMalformed)
+	}
+
+	// Canonical decoding: codes of each length are consecutive, starting at first[len]
+	sortSymbols(symbols)
+	var lenCount, first, index [maxHuffmanLen + 1]int64
+	for _, s := range symbols {
+		lenCount[s.len]++
+	}
+	for l, code, i := 1, int64(0), int64(0); l <= maxHuffmanLen; l++ {
+		code = (code + lenCount[l-1]) << 1
+		first[l], index[l] = code, i
+		i += lenCount[l]
+	}
+
+	dst := make([]byte, 0, n)
+	code, l := int64(0), 0
+	for _, b := range src {
+		for bit := 7; bit >= 0; bit-- {
+			code = code<<1 | int64(b>>uint(bit)&1)
+			l++
+			if offset := code - first[l]; offset < lenCount[l] {
+				dst = append(dst, symbols[index[l]+offset].value)
+				if uint64(len(dst)) == n {
+					return dst, nil
+				}
+				code, l = 0, 0
+			} else if l == maxHuffmanLen {
+				return nil, fmt.Errorf("%w: invalid Huffman code", ErrMalformed)
+			}
+		}
+	}
+	return nil, fmt.Errorf("%w: Huffman data is truncated", ErrMalformed)
+}
+
+// huffmanLengths returns the symbols present in freq with their code lengths, in canonical order
+func huffmanLengths(freq [256]int) []huffmanSymbol {
+	for {
+		h := &huffmanHeap{}
+		for v, f := range freq {
+			if f > 0 {
+				*h = append(*h, &huffmanNode{weight: f, value: byte(v), leaf: true})
+			}
+		}
+		if len(*h) == 1 {
+			return []huffmanSymbol{{value: (*h)[0].value, len: 1}}
+		}
+
+		heap.Init(h)
+		for h.Len() > 1 {
+			a, b := heap.Pop(h).(*huffmanNode), heap.Pop(h).(*huffmanNode)
+			heap.Push(h, &huffmanNode{weight: a.weight + b.weight, left: a, right: b})
+		}
+
+		var symbols []huffmanSymbol
+		tooLong := false
+		var walk func(node *huffmanNode, depth int)
+		walk = func(node *huffmanNode, depth int) {
+			if node.leaf {
+				symbols = append(symbols, huffmanSymbol{value: node.value, len: depth})
+				tooLong = tooLong || depth > maxHuffmanLen
+				return
+			}
+			walk(node.left, depth+1)
+			walk(node.right, depth+1)
+		}
+		walk((*h)[0], 0)
+		if !tooLong {
+			sortSymbols(symbols)
+			return symbols
+		}
+
+		// Flatten the distribution and try again
+		for v := range freq {
+			if freq[v] > 0 {
+				freq[v] = freq[v]/2 + 1
+			}
+		}
+	}
+}
+
+// huffmanCode is the code of a symbol
+type huffmanCode struct {
+	bits uint64
+	len  int
+}
+
+// canonicalCodes assigns canonical codes to symbols sorted by length and value
+func canonicalCodes(symbols []huffmanSymbol) [256]huffmanCode {
+	var codes [256]huffmanCode
+	code, prevLen := uint64(0), symbols[0].len
+	for _, s := range symbols {
+		code <<= uint(s.len - prevLen)
+		codes[s.value] = huffmanCode{bits: code, len: s.len}
+		code++
+		prevLen = s.len
+	}
+	return codes
+}
+
+// sortSymbols orders symbols by code length, then by value
+func sortSymbols(symbols []huffmanSymbol) {
+	sort.Slice(symbols, func(i, j int) bool {
+		if symbols[i].len != symbols[j].len {
+			return symbols[i].len < symbols[j].len
+		}
+		return symbols[i].value < symbols[j].value
+	})
+}
+
+// huffmanNode is a node of the Huffman tree
+type huffmanNode struct {
+	weight      int
+	value       byte
+	leaf        bool
+	left, right *huffmanNode
+}
+
+// huffmanHeap is a min-heap of nodes by weight, with ties broken by value for deterministic output
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].weight != h[j].weight {
+		return h[i].weight < h[j].weight
+	}
+	return h[i].value < h[j].value
+}
+func (h huffmanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x interface{}) { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() interface{} {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
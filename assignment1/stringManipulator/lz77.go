@@ -0,0 +1,149 @@
+package stringManipulator
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// LZ77 parameters
+const (
+	lz77Window   = 1 << 15 // furthest back a match may start
+	lz77MinMatch = 4       // shortest match worth writing
+	lz77MaxMatch = 1 << 16 // longest match written at once
+	lz77MaxChain = 32      // candidates examined per position
+	lz77HashBits = 15
+)
+
+// lz77Codec is a sliding-window LZ77 coder.
+// The output is the uvarint input length followed by sequences, each made of the uvarint literal count,
+// the literals and, unless it is the final sequence, the uvarint match length minus lz77MinMatch and the uvarint distance.
+type lz77Codec struct{}
+
+func (lz77Codec) Name() string { return "lz77" }
+func (lz77Codec) ID() byte     { return LZ77ID }
+
+func (lz77Codec) Encode(src []byte) ([]byte, error) {
+	var varint [binary.MaxVarintLen64]byte
+	putUvarint := func(dst []byte, v int) []byte {
+		return append(dst, varint[:binary.PutUvarint(varint[:], uint64(v))]...)
+	}
+
+	dst := putUvarint(make([]byte, 0, len(src)/2+binary.MaxVarintLen64), len(src))
+	head := make([]int, 1<<lz77HashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int, len(src))
+
+	insert := func(i int) {
+		if i+lz77MinMatch <= len(src) {
+			h := lz77Hash(src[i:])
+			prev[i] = head[h]
+			head[h] = i
+		}
+	}
+
+	literals := 0
+	for i := 0; i < len(src); {
+		bestLen, bestDist := 0, 0
+		if i+lz77MinMatch <= len(src) {
+			for j, chain := head[lz77Hash(src[i:])], 0; j >= 0 && i-j <= lz77Window && chain < lz77MaxChain; j, chain = prev[j], chain+1 {
+				n := matchLen(src[j:], src[i:])
+				if n > bestLen {
+					bestLen, bestDist = n, i-j
+				}
+			}
+		}
+
+		if bestLen < lz77MinMatch {
+			insert(i)
+			literals++
+			i++
+			continue
+		}
+
+		dst = putUvarint(dst, literals)
+		dst = append(dst, src[i-literals:i]...)
+		dst = putUvarint(dst, bestLen-lz77MinMatch)
+		dst = putUvarint(dst, bestDist)
+		for end := i + bestLen; i < end; i++ {
+			insert(i)
+		}
+		literals = 0
+	}
+	dst = putUvarint(dst, literals)
+	dst = append(dst, src[len(src)-literals:]...)
+	return dst, nil
+}
+
+func (l lz77Codec) Decode(src []byte) ([]byte, error) {
+	return l.DecodeLimit(src, maxInt)
+}
+
+func (lz77Codec) DecodeLimit(src []byte, limit int) ([]byte, error) {
+	n, k := binary.Uvarint(src)
+	if k <= 0 {
+		return nil, fmt.Errorf("%w: LZ77 length is truncated", ErrMalformed)
+	}
+	if n > uint64(limit) {
+		return nil, ErrLength
+	}
+	src = src[k:]
+
+	capacity := 64*len(src) + 64
+	if n < uint64(capacity) {
+		capacity = int(n)
+	}
+	dst := make([]byte, 0, capacity)
+	for {
+		literals, k := binary.Uvarint(src)
+		if k <= 0 || literals > uint64(len(src)-k) {
+			return nil, fmt.Errorf("%w: LZ77 literals are truncated", ErrMalformed)
+		}
+		dst = append(dst, src[k:k+int(literals)]...)
+		src = src[k+int(literals):]
+		if len(src) == 0 {
+			break
+		}
+
+		length, k := binary.Uvarint(src)
+		if k <= 0 || length > lz77MaxMatch-lz77MinMatch {
+			return nil, fmt.Errorf("%w: LZ77 match length is invalid", ErrMalformed)
+		}
+		src = src[k:]
+		dist, k := binary.Uvarint(src)
+		if k <= 0 || dist == 0 || dist > uint64(len(dst)) {
+			return nil, fmt.Errorf("%w: LZ77 match distance is invalid", ErrMalformed)
+		}
+		src = src[k:]
+
+		// Copy byte by byte, since a match may overlap the bytes it produces
+		start := len(dst) - int(dist)
+		for j := 0; j < int(length)+lz77MinMatch; j++ {
+			dst = append(dst, dst[start+j])
+		}
+		if uint64(len(dst)) > n {
+			return nil, fmt.Errorf("%w: LZ77 output is longer than its header", ErrMalformed)
+		}
+	}
+
+	if uint64(len(dst)) != n {
+		return nil, fmt.Errorf("%w: LZ77 output is shorter than its header", ErrMalformed)
+	}
+	return dst, nil
+}
+
+// lz77Hash hashes the first lz77MinMatch bytes of b
+func lz77Hash(b []byte) uint32 {
+	v := binary.LittleEndian.Uint32(b)
+	return (v * 2654435761) >> (32 - lz77HashBits)
+}
+
+// matchLen returns the length of the common prefix of a and b, up to lz77MaxMatch
+func matchLen(a, b []byte) int {
+	n := 0
+	for n < len(a) && n < len(b) && n < lz77MaxMatch && a[n] == b[n] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,85 @@
+package stringManipulator
+
+import (
+	"strconv"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization selects the Unicode normalization form applied to the input before runs are detected
+type Normalization int
+
+const (
+	// NormNone leaves the input as it is
+	NormNone Normalization = iota
+	// NFC composes characters, so "é" and "é" become the same single rune
+	NFC
+	// NFKC also replaces compatibility characters, e.g. "ﬁ" becomes "fi". It loses more information than NFC.
+	NFKC
+)
+
+// String returns the name of the normalization form
+func (n Normalization) String() string {
+	switch n {
+	case NormNone:
+		return "none"
+	case NFC:
+		return "NFC"
+	case NFKC:
+		return "NFKC"
+	}
+	return "Normalization(" + strconv.Itoa(int(n)) + ")"
+}
+
+// Flags recorded in the header of FormatEscaped output when the input was changed before compression
+const (
+	flagNFC = 1 << iota
+	flagNFKC
+	flagCaseFold
+)
+
+// flagBase is added to the flags to write them as a printable byte
+const flagBase = 'a'
+
+// Largest growth in bytes of the changes normalize makes: full case folding turns U+0390 into three runes,
+// NFC expands text at most three times and NFKC at most 18 times, as for "\ufdfa"
+const (
+	caseFoldExpansion = 3
+	nfcExpansion      = 3
+	nfkcExpansion     = 18
+)
+
+// normalizeExpansion returns how many times longer normalize can make its input with opts
+func normalizeExpansion(opts Options) int {
+	factor := 1
+	if opts.CaseFold {
+		factor *= caseFoldExpansion
+	}
+	switch opts.Normalize {
+	case NFC:
+		factor *= nfcExpansion
+	case NFKC:
+		factor *= nfkcExpansion
+	}
+	return factor
+}
+
+// normalize returns s case-folded and normalized as opts request, and the header flags recording what was done
+func (opts Options) normalize(s string) (string, int) {
+	flags := 0
+	if opts.CaseFold {
+		s = cases.Fold().String(s)
+		flags |= flagCaseFold
+	}
+	// Normalizing after folding, since folding may produce decomposed text
+	switch opts.Normalize {
+	case NFC:
+		s = norm.NFC.String(s)
+		flags |= flagNFC
+	case NFKC:
+		s = norm.NFKC.String(s)
+		flags |= flagNFKC
+	}
+	return s, flags
+}
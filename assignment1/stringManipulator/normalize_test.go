@@ -0,0 +1,89 @@
+package stringManipulator
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/text/unicode/norm"
+)
+
+var normalizeTests = []struct {
+	name     string
+	input    string
+	opts     Options
+	expected string
+	unpacked string
+}{
+	{"NFD input", "e\u0301e\u0301e\u0301", Options{Format: FormatEscaped, Normalize: NFC}, "\\2b\u00e93", "\u00e9\u00e9\u00e9"},
+	{"NFC input", "\u00e9\u00e9\u00e9", Options{Format: FormatEscaped, Normalize: NFC}, "\\1\u00e93", "\u00e9\u00e9\u00e9"},
+	{"ligatures", "\ufb01\ufb01", Options{Format: FormatEscaped, Normalize: NFKC}, "\\2cfifi", "fifi"},
+	{"mixed case", "aAaA", Options{Format: FormatEscaped, CaseFold: true}, "\\2ea4", "aaaa"},
+	{"folded NFD input", "E\u0301e\u0301", Options{Format: FormatEscaped, Normalize: NFC, CaseFold: true}, "\\2f\u00e92", "\u00e9\u00e9"},
+	{"lower case", "aaaa", Options{Format: FormatEscaped, CaseFold: true}, "\\1a4", "aaaa"},
+}
+
+func TestCompressNormalized(t *testing.T) {
+	for _, test := range normalizeTests {
+		t.Logf("CompressWith() should normalize %s before detecting runs.", test.name)
+		result, err := CompressWith(test.input, test.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+
+		t.Logf("UnpackWith() should return the normalized %s.", test.name)
+		result, err = UnpackWith(result, Options{Format: FormatEscaped})
+		assert.NoError(t, err)
+		assert.Equal(t, test.unpacked, result)
+	}
+}
+
+func TestParseEscapedHeader(t *testing.T) {
+	for _, test := range normalizeTests {
+		compressed, err := CompressWith(test.input, test.opts)
+		assert.NoError(t, err)
+		h, err := ParseEscapedHeader(compressed)
+		assert.NoError(t, err)
+
+		t.Logf("ParseEscapedHeader() should report whether normalizing %s was lossy.", test.name)
+		assert.Equal(t, test.input != test.unpacked, h.Lossy())
+		if h.Lossy() {
+			assert.Equal(t, EscapedVersionNormalized, h.Version)
+			assert.Equal(t, test.opts.Normalize, h.Normalize)
+			assert.Equal(t, test.opts.CaseFold, h.CaseFold)
+			assert.Equal(t, 3, h.Size)
+		} else {
+			assert.Equal(t, EscapedHeader{Escape: '\\', Version: EscapedVersion, Size: 2}, h)
+		}
+	}
+}
+
+func TestNormalizedRoundTrip(t *testing.T) {
+	t.Log("UnpackWith(CompressWith()) should return the normalized input, and the header should say when it differs.")
+	r := rand.New(rand.NewSource(4))
+	alphabet := []string{"e", "E", "\u0301", "\u00e9", "\u00c9", "\ufb01", "1", "\\", "\u212b", "A\u030a", "\xff"}
+	for i := 0; i < 300; i++ {
+		s := randomText(r, alphabet, 20, 5)
+		for _, form := range []Normalization{NFC, NFKC} {
+			opts := Options{Format: FormatEscaped, Normalize: form, CaseFold: i%2 == 0}
+			compressed, err := CompressWith(s, opts)
+			assert.NoError(t, err)
+			result, err := UnpackWith(compressed, Options{Format: FormatEscaped})
+			assert.NoError(t, err)
+			h, err := ParseEscapedHeader(compressed)
+			assert.NoError(t, err)
+			assert.Equal(t, result != s, h.Lossy(), "%q", s)
+			if form == NFC && !strings.Contains(s, "\xff") {
+				assert.True(t, norm.NFC.IsNormalString(result))
+			}
+		}
+	}
+}
+
+func TestNormalizeRequiresEscaped(t *testing.T) {
+	t.Log("CompressWith() should reject normalization without a header to record it.")
+	for _, opts := range []Options{{Normalize: NFC}, {CaseFold: true}, {CountEncoding: Varint, Normalize: NFKC}, {Format: FormatEscaped, Normalize: 3}} {
+		_, err := CompressWith("a", opts)
+		assert.Equal(t, ErrInvalidOptions, err)
+	}
+}
@@ -0,0 +1,404 @@
+package stringManipulator
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// CountEncoding selects how run lengths are written
+type CountEncoding int
+
+const (
+	// Decimal writes counts as digits directly after the character. Counts of one are omitted.
+	// Multi-digit counts require FormatEscaped, since the legacy format cannot tell them apart from literal digits.
+	Decimal CountEncoding = iota
+	// Varint writes every run as the uvarint byte length of the character, the character and the uvarint count.
+	// The output is binary and is not valid UTF-8 in general.
+	Varint
+	// Delimited writes counts as decimal digits between DelimOpen and DelimClose, e.g. "a{1000}".
+	// Literal digits need no escaping, so runs of any length round-trip in both formats.
+	Delimited
+)
+
+// String returns the name of the count encoding
+func (c CountEncoding) String() string {
+	switch c {
+	case Decimal:
+		return "decimal"
+	case Varint:
+		return "varint"
+	case Delimited:
+		return "delimited"
+	}
+	return "CountEncoding(" + strconv.Itoa(int(c)) + ")"
+}
+
+// Delimiters around counts written with Delimited
+const (
+	DelimOpen  = '{'
+	DelimClose = '}'
+)
+
+// ErrInvalidOptions is returned when a combination of options cannot be encoded unambiguously
+var ErrInvalidOptions = errors.New("stringManipulator: invalid options")
+
+// Unit selects what is counted as one repeated character
+type Unit int
+
+const (
+	// UnitRune repeats single runes. Invalid UTF-8 bytes are treated as one character each.
+	UnitRune Unit = iota
+	// UnitGrapheme repeats extended grapheme clusters (UAX #29), so emoji sequences, flags and combining accents stay whole
+	UnitGrapheme
+	// UnitEntity repeats HTML and XML character references such as "&#39;" and "&amp;", and backslash escape sequences
+	// such as "\n" and "\u00e9", as whole units. Any other rune is a unit of its own.
+	// With FormatEscaped, the escape rune must not be '\\' or '&' and defaults to EntityEscape.
+	// With FormatLegacy, the count encoding must be Varint or Delimited: decimal counts cannot be told apart
+	// from the digits of a literal such as "&#" followed by '#' and ';', which would be read back as a reference.
+	UnitEntity
+)
+
+// String returns the name of the unit
+func (u Unit) String() string {
+	switch u {
+	case UnitRune:
+		return "rune"
+	case UnitGrapheme:
+		return "grapheme"
+	case UnitEntity:
+		return "entity"
+	}
+	return "Unit(" + strconv.Itoa(int(u)) + ")"
+}
+
+// Options configures CompressWith and UnpackWith. The zero value matches Compress and Unpack.
+type Options struct {
+	Format        Format        // header and escaping of literal digits
+	Escape        rune          // escape rune for FormatEscaped, 0 selects DefaultEscape, or EntityEscape with UnitEntity
+	MaxRun        int           // longest run written with a single count, 0 selects the longest the encoding allows
+	CountEncoding CountEncoding // how counts are written
+	Unit          Unit          // what is counted as one character
+	Normalize     Normalization // normalization form applied before runs are detected; requires FormatEscaped
+	CaseFold      bool          // whether to case-fold before runs are detected; requires FormatEscaped
+	MaxOutput     int           // largest output UnpackWith produces before failing, 0 selects DefaultMaxOutput
+}
+
+// DefaultMaxOutput is the largest output UnpackWith produces by default.
+// A count of a few bytes can ask for any length, so decoding untrusted input must be bounded.
+const DefaultMaxOutput = 1 << 28
+
+// withDefaults validates opts and fills in the defaults
+func (opts Options) withDefaults() (Options, error) {
+	switch {
+	case opts.Escape == 0 && opts.Unit == UnitEntity:
+		opts.Escape = EntityEscape
+	case opts.Escape == 0:
+		opts.Escape = DefaultEscape
+	}
+	if !validEscape(opts.Escape) || (opts.CountEncoding == Delimited && opts.Escape == DelimOpen) {
+		return opts, ErrInvalidEscape
+	}
+
+	switch opts.Format {
+	case FormatLegacy, FormatEscaped:
+	default:
+		return opts, ErrInvalidOptions
+	}
+	switch opts.CountEncoding {
+	case Decimal, Varint, Delimited:
+	default:
+		return opts, ErrInvalidOptions
+	}
+	switch opts.Unit {
+	case UnitRune:
+	case UnitGrapheme:
+		// Clusters are found again while decoding, so the escape must never join the cluster in front of it
+		if opts.Escape < ' ' || opts.Escape >= utf8.RuneSelf {
+			return opts, ErrInvalidEscape
+		}
+	case UnitEntity:
+		// An escaped unit is a single rune, so the escape cannot also start references
+		if opts.Escape == '\\' || opts.Escape == '&' {
+			return opts, ErrInvalidEscape
+		}
+		if opts.Format == FormatLegacy && opts.CountEncoding == Decimal {
+			return opts, ErrInvalidOptions
+		}
+	default:
+		return opts, ErrInvalidOptions
+	}
+
+	switch opts.Normalize {
+	case NormNone, NFC, NFKC:
+	default:
+		return opts, ErrInvalidOptions
+	}
+	// Only the escaped format has a header to record that the input was changed
+	if (opts.Normalize != NormNone || opts.CaseFold) && opts.Format != FormatEscaped {
+		return opts, ErrInvalidOptions
+	}
+
+	legacyDecimal := opts.Format == FormatLegacy && opts.CountEncoding == Decimal
+	switch {
+	case opts.MaxRun < 0, legacyDecimal && opts.MaxRun > maxLegacyRun:
+		return opts, ErrInvalidOptions
+	case opts.MaxRun == 0 && legacyDecimal:
+		opts.MaxRun = maxLegacyRun
+	case opts.MaxRun == 0:
+		opts.MaxRun = maxInt
+	}
+	switch {
+	case opts.MaxOutput < 0:
+		return opts, ErrInvalidOptions
+	case opts.MaxOutput == 0:
+		opts.MaxOutput = DefaultMaxOutput
+	}
+	return opts, nil
+}
+
+// CompressBound returns the largest output CompressWith can produce for an input of n bytes with opts
+func CompressBound(n int, opts Options) int {
+	if opts.Format == FormatLegacy && opts.CountEncoding == Decimal {
+		return n // a run never takes more bytes than it encodes
+	}
+
+	// A single-byte character can gain an escape, a varint length and count, or a delimited count
+	perByte := 3
+	if opts.Format == FormatEscaped {
+		escape := opts.Escape
+		if escape == 0 {
+			escape = DefaultEscape
+		}
+		perByte = utf8.RuneLen(escape) + 4
+		n *= normalizeExpansion(opts)
+	}
+	return perByte*n + escapedHeaderMax
+}
+
+// CompressWith compresses s using the format, count encoding and run limit in opts
+func CompressWith(s string, opts Options) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	if len(s) < 1 {
+		return s, nil
+	}
+
+	var b strings.Builder
+	if opts.Format == FormatEscaped {
+		// Version 2 records the changes made to the input, so callers know the round-trip is not exact
+		normalized, flags := opts.normalize(s)
+		b.Grow(len(normalized) + escapedHeaderMax)
+		b.WriteRune(opts.Escape)
+		if normalized == s {
+			b.WriteByte('0' + EscapedVersion)
+		} else {
+			b.WriteByte('0' + EscapedVersionNormalized)
+			b.WriteByte(byte(flagBase + flags))
+		}
+		s = normalized
+	} else {
+		b.Grow(len(s))
+	}
+
+	unitLen := opts.unitFunc()
+	var varint [binary.MaxVarintLen64]byte
+	for i := 0; i < len(s); {
+		size := unitLen(s[i:])
+		char := s[i : i+size]
+		count := 1
+		for i += size; i < len(s) && count < opts.MaxRun && strings.HasPrefix(s[i:], char) && unitLen(s[i:]) == size; i += size {
+			count++
+		}
+
+		if opts.CountEncoding == Varint {
+			b.Write(varint[:binary.PutUvarint(varint[:], uint64(size))])
+			b.WriteString(char)
+			b.Write(varint[:binary.PutUvarint(varint[:], uint64(count))])
+			continue
+		}
+
+		if opts.Format == FormatEscaped && opts.needsEscape(char) {
+			b.WriteRune(opts.Escape)
+		}
+		b.WriteString(char)
+		switch {
+		case opts.CountEncoding == Delimited && (count > 1 || (i < len(s) && s[i] == DelimOpen)):
+			b.WriteByte(DelimOpen)
+			b.WriteString(strconv.Itoa(count))
+			b.WriteByte(DelimClose)
+		case opts.CountEncoding == Decimal && count > 1:
+			b.WriteString(strconv.Itoa(count))
+		}
+	}
+
+	return b.String(), nil
+}
+
+// UnpackWith reverses CompressWith. opts must match the options s was compressed with,
+// except that the escape rune of FormatEscaped is read from the header.
+// Errors other than invalid options are *SyntaxError, including output longer than opts.MaxOutput.
+func UnpackWith(s string, opts Options) (string, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return "", err
+	}
+	if len(s) < 1 {
+		return s, nil
+	}
+	if opts.Format == FormatLegacy && opts.CountEncoding == Decimal && opts.Unit == UnitRune {
+		// Counts are single digits, so the output is at most nine times the input before it is checked
+		out := Unpack(s)
+		if len(out) > opts.MaxOutput {
+			return "", newSyntaxError(s, len(s), "output within the limit of "+strconv.Itoa(opts.MaxOutput)+" bytes")
+		}
+		return out, nil
+	}
+
+	i := 0
+	if opts.Format == FormatEscaped {
+		h, err := ParseEscapedHeader(s)
+		if err != nil {
+			return "", err
+		}
+		opts.Escape = h.Escape
+		i = h.Size
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i < len(s) {
+		var char string
+		var count int
+		start := i
+		if opts.CountEncoding == Varint {
+			char, count, i, err = readVarintRun(s, i)
+		} else {
+			char, count, i, err = readTextRun(s, i, opts)
+		}
+		if err != nil {
+			return "", err
+		}
+		if count > (opts.MaxOutput-b.Len())/len(char) {
+			return "", newSyntaxError(s, start, "a run within the output limit of "+strconv.Itoa(opts.MaxOutput)+" bytes")
+		}
+		b.WriteString(strings.Repeat(char, count))
+	}
+
+	return b.String(), nil
+}
+
+// readTextRun reads the Decimal or Delimited run starting at s[i] and returns the offset following it
+func readTextRun(s string, i int, opts Options) (char string, count int, next int, err error) {
+	legacyDecimal := opts.Format == FormatLegacy && opts.CountEncoding == Decimal
+	r, size := utf8.DecodeRuneInString(s[i:])
+	escaped := opts.Format == FormatEscaped && r == opts.Escape
+	switch {
+	case escaped:
+		i += size
+		if i == len(s) {
+			return "", 0, i, newSyntaxError(s, i, "a character after the escape")
+		}
+	case opts.CountEncoding == Decimal && isDigit(r) && !legacyDecimal:
+		return "", 0, i, newSyntaxError(s, i, "a character before the count")
+	}
+	unitLen := opts.unitFunc()
+	if escaped && opts.Unit == UnitEntity {
+		unitLen = runeLen // an escaped unit is never a reference
+	}
+	size = unitLen(s[i:])
+	char = s[i : i+size]
+	i += size
+
+	j := i
+	switch {
+	case legacyDecimal:
+		// Like Unpack, a single digit after a character is its count and any other digit is literal
+		if i < len(s) && isDigit(rune(s[i])) {
+			return char, int(s[i] - '0'), i + 1, nil
+		}
+		return char, 1, i, nil
+	case opts.CountEncoding == Decimal:
+		for j < len(s) && isDigit(rune(s[j])) {
+			j++
+		}
+		if j == i {
+			return char, 1, i, nil
+		}
+	case i < len(s) && s[i] == DelimOpen:
+		i++
+		j = strings.IndexByte(s[i:], DelimClose)
+		if j < 0 {
+			return "", 0, i, newSyntaxError(s, len(s), "a closing "+string(DelimClose))
+		}
+		j += i
+	default:
+		return char, 1, i, nil
+	}
+
+	least := 2
+	if opts.CountEncoding == Delimited {
+		least = 1
+	}
+	count, err = strconv.Atoi(s[i:j])
+	if err != nil || count < least || s[i] < '1' || s[i] > '9' || count > maxInt/len(char) {
+		return "", 0, i, newSyntaxError(s, i, "a count of at least "+strconv.Itoa(least))
+	}
+	if opts.CountEncoding == Delimited {
+		j++
+	}
+	return char, count, j, nil
+}
+
+// readVarintRun reads the Varint run starting at s[i] and returns the offset following it
+func readVarintRun(s string, i int) (char string, count int, next int, err error) {
+	size, n := binary.Uvarint([]byte(s[i:min(len(s), i+binary.MaxVarintLen64)]))
+	if n <= 0 || size == 0 || size > uint64(len(s)-i-n) {
+		return "", 0, i, newSyntaxError(s, i, "the length of a character")
+	}
+	i += n
+	char = s[i : i+int(size)]
+	i += int(size)
+
+	c, n := binary.Uvarint([]byte(s[i:min(len(s), i+binary.MaxVarintLen64)]))
+	if n <= 0 || c == 0 || c > uint64(maxInt/len(char)) {
+		return "", 0, i, newSyntaxError(s, i, "a count of at least 1")
+	}
+	return char, int(c), i + n, nil
+}
+
+// unitFunc returns a function reporting the byte length of the first unit of a string
+func (opts Options) unitFunc() func(string) int {
+	switch opts.Unit {
+	case UnitGrapheme:
+		return graphemeLen
+	case UnitEntity:
+		return entityLen
+	}
+	return runeLen
+}
+
+// needsEscape reports whether the unit char is written with the escape rune in front of it in FormatEscaped.
+// Digits and the escape rune always are. With UnitEntity, so are the '&' and '\\' that do not start a reference,
+// which would otherwise be read back as part of one when a count follows them.
+func (opts Options) needsEscape(char string) bool {
+	r, _ := utf8.DecodeRuneInString(char)
+	return isDigit(r) || r == opts.Escape || opts.Unit == UnitEntity && (char == "&" || char == "\\")
+}
+
+// runeLen returns the byte length of the first rune of s
+func runeLen(s string) int {
+	_, size := utf8.DecodeRuneInString(s)
+	return size
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
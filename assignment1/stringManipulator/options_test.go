@@ -0,0 +1,163 @@
+package stringManipulator
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var optionsTests = []struct {
+	input    string
+	opts     Options
+	expected string
+}{
+	{
+		strings.Repeat("a", 1000),
+		Options{Format: FormatEscaped},
+		`\1a1000`,
+	},
+	{
+		strings.Repeat("a", 1000),
+		Options{Format: FormatEscaped, MaxRun: 300},
+		`\1a300a300a300a100`,
+	},
+	{
+		strings.Repeat("a", 1000) + "11",
+		Options{CountEncoding: Delimited},
+		"a{1000}1{2}",
+	},
+	{
+		"x{{{y{",
+		Options{CountEncoding: Delimited},
+		"x{1}{{3}y{1}{",
+	},
+	{
+		strings.Repeat("a", 1000) + "€",
+		Options{CountEncoding: Varint},
+		"\x01a\xe8\x07\x03€\x01",
+	},
+	{
+		"aaaa1",
+		Options{Format: FormatEscaped, Escape: '#', CountEncoding: Delimited},
+		"#1a{4}#1",
+	},
+}
+
+func TestCompressWith(t *testing.T) {
+	for _, test := range optionsTests {
+		t.Logf("CompressWith() should return %q when the options are %+v", test.expected, test.opts)
+		result, err := CompressWith(test.input, test.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+	}
+}
+
+func TestUnpackWith(t *testing.T) {
+	for _, test := range optionsTests {
+		t.Logf("UnpackWith() should return the input when the options are %+v", test.opts)
+		result, err := UnpackWith(test.expected, test.opts)
+		assert.NoError(t, err)
+		assert.Equal(t, test.input, result)
+	}
+}
+
+func TestCompressWithZeroOptions(t *testing.T) {
+	for i := 0; i < len(tests); i++ {
+		input, expectedResult := tests[i][0], tests[i][1]
+		t.Logf("CompressWith() should match Compress() when the input is %s", input)
+		result, err := CompressWith(input, Options{})
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestOptionsRoundTrip(t *testing.T) {
+	inputs := []string{
+		"",
+		"11111111111111111111",
+		strings.Repeat("a", 1000) + strings.Repeat("9", 12),
+		"{{}}{1}{{2}}",
+		"\\\\#1##2",
+		"€€€😀😀\xff\xff\xe2\x82",
+	}
+	for _, format := range []Format{FormatLegacy, FormatEscaped} {
+		for _, countEncoding := range []CountEncoding{Varint, Delimited} {
+			for _, maxRun := range []int{0, 1, 7} {
+				opts := Options{Format: format, CountEncoding: countEncoding, MaxRun: maxRun}
+				for _, input := range inputs {
+					t.Logf("UnpackWith(CompressWith()) should return %q when the options are %+v", input, opts)
+					compressed, err := CompressWith(input, opts)
+					assert.NoError(t, err)
+					result, err := UnpackWith(compressed, opts)
+					assert.NoError(t, err)
+					assert.Equal(t, input, result)
+				}
+			}
+		}
+	}
+}
+
+func TestOptionsInvalid(t *testing.T) {
+	t.Log("CompressWith() should reject options that cannot be decoded unambiguously.")
+	_, err := CompressWith("a", Options{MaxRun: 10})
+	assert.Equal(t, ErrInvalidOptions, err)
+	_, err = CompressWith("a", Options{Format: FormatEscaped, MaxRun: -1})
+	assert.Equal(t, ErrInvalidOptions, err)
+	_, err = CompressWith("a", Options{CountEncoding: CountEncoding(7)})
+	assert.Equal(t, ErrInvalidOptions, err)
+	_, err = CompressWith("a", Options{Format: FormatEscaped, Escape: DelimOpen, CountEncoding: Delimited})
+	assert.Equal(t, ErrInvalidEscape, err)
+	_, err = UnpackWith("a", Options{MaxOutput: -1})
+	assert.Equal(t, ErrInvalidOptions, err)
+}
+
+func TestUnpackWithMaxOutput(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  Options
+	}{
+		{"\\1a9223372036854775807", Options{Format: FormatEscaped}},
+		{"\\1a268435457", Options{Format: FormatEscaped}},
+		{"\x01a\x80\x80\x80\x80\x80\x80\x80\x80\x40", Options{CountEncoding: Varint}},
+		{"a{1000000000000}", Options{CountEncoding: Delimited}},
+		{"\\1a3b3", Options{Format: FormatEscaped, MaxOutput: 5}},
+		{"a3b3", Options{MaxOutput: 5}},
+	}
+	for _, test := range tests {
+		t.Logf("UnpackWith(%q) should fail instead of producing more than the output limit.", test.input)
+		_, err := UnpackWith(test.input, test.opts)
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr), "got %v", err)
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+
+	t.Log("UnpackWith() should produce output up to the limit.")
+	out, err := UnpackWith("\\1a3b3", Options{Format: FormatEscaped, MaxOutput: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, "aaabbb", out)
+	out, err = UnpackWith("a3b3", Options{MaxOutput: 6})
+	assert.NoError(t, err)
+	assert.Equal(t, "aaabbb", out)
+}
+
+func TestUnpackWithFails(t *testing.T) {
+	tests := []struct {
+		input string
+		opts  Options
+	}{
+		{"a{3", Options{CountEncoding: Delimited}},
+		{"a{0}", Options{CountEncoding: Delimited}},
+		{"a{+3}", Options{CountEncoding: Delimited}},
+		{"\x05ab", Options{CountEncoding: Varint}},
+		{"\x01a", Options{CountEncoding: Varint}},
+		{"\x01a\x00", Options{CountEncoding: Varint}},
+	}
+	for _, test := range tests {
+		t.Logf("UnpackWith() should return a SyntaxError when the input is %q", test.input)
+		_, err := UnpackWith(test.input, test.opts)
+		var syntaxErr *SyntaxError
+		assert.True(t, errors.As(err, &syntaxErr))
+	}
+}
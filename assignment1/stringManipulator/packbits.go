@@ -0,0 +1,67 @@
+package stringManipulator
+
+import (
+	"fmt"
+)
+
+// maxPackBitsRun is the longest literal or repeat run a single PackBits header can describe
+const maxPackBitsRun = 128
+
+// CompressBytes run-length encodes binary data using the Apple PackBits layout.
+// Each run starts with a signed header byte n: 0 to 127 is followed by n+1 literal bytes,
+// -1 to -127 is followed by one byte repeated 1-n times, and -128 is skipped.
+// Repeats of three or more bytes are written as repeat runs, everything else as literal runs.
+func CompressBytes(src []byte) []byte {
+	dst := make([]byte, 0, len(src)+len(src)/maxPackBitsRun+1)
+	for i := 0; i < len(src); {
+		if n := repeatLen(src[i:]); n >= 3 {
+			dst = append(dst, byte(1-n), src[i])
+			i += n
+			continue
+		}
+
+		j := i + 1
+		for j < len(src) && j-i < maxPackBitsRun && repeatLen(src[j:]) < 3 {
+			j++
+		}
+		dst = append(dst, byte(j-i-1))
+		dst = append(dst, src[i:j]...)
+		i = j
+	}
+	return dst
+}
+
+// UnpackBytes reverses CompressBytes and decodes PackBits data written by other tools
+func UnpackBytes(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, 2*len(src))
+	for i := 0; i < len(src); {
+		n := int(int8(src[i]))
+		i++
+		switch {
+		case n >= 0:
+			if i+n+1 > len(src) {
+				return nil, fmt.Errorf("%w: PackBits literal run at byte %d is truncated", ErrMalformed, i-1)
+			}
+			dst = append(dst, src[i:i+n+1]...)
+			i += n + 1
+		case n > -maxPackBitsRun:
+			if i == len(src) {
+				return nil, fmt.Errorf("%w: PackBits repeat run at byte %d is truncated", ErrMalformed, i-1)
+			}
+			for j := 0; j < 1-n; j++ {
+				dst = append(dst, src[i])
+			}
+			i++
+		}
+	}
+	return dst, nil
+}
+
+// repeatLen returns how many times the first byte of b repeats, up to maxPackBitsRun
+func repeatLen(b []byte) int {
+	n := 1
+	for n < len(b) && n < maxPackBitsRun && b[n] == b[0] {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// packBitsTests are known PackBits vectors, the first from Apple Technical Note TN1023
+var packBitsTests = [][][]byte{
+	{
+		{0xAA, 0xAA, 0xAA, 0x80, 0x00, 0x2A, 0xAA, 0xAA, 0xAA, 0xAA, 0x80, 0x00, 0x2A, 0x22, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA, 0xAA},
+		{0xFE, 0xAA, 0x02, 0x80, 0x00, 0x2A, 0xFD, 0xAA, 0x03, 0x80, 0x00, 0x2A, 0x22, 0xF7, 0xAA},
+	},
+	{
+		{},
+		{},
+	},
+	{
+		{0x00},
+		{0x00, 0x00},
+	},
+	{
+		bytes.Repeat([]byte{0x00}, 130),
+		{0x81, 0x00, 0x01, 0x00, 0x00},
+	},
+	{
+		append(bytes.Repeat([]byte{0x01, 0x02}, 65), 0x03),
+		append(append(append([]byte{0x7F}, bytes.Repeat([]byte{0x01, 0x02}, 64)...), 0x02, 0x01, 0x02), 0x03),
+	},
+}
+
+func TestCompressBytes(t *testing.T) {
+	for _, test := range packBitsTests {
+		input, expectedResult := test[0], test[1]
+		t.Logf("CompressBytes() should return % X when the input is % X", expectedResult, input)
+		assert.Equal(t, expectedResult, CompressBytes(input))
+	}
+}
+
+func TestUnpackBytes(t *testing.T) {
+	for _, test := range packBitsTests {
+		input, expectedResult := test[1], test[0]
+		t.Logf("UnpackBytes() should return % X when the input is % X", expectedResult, input)
+		result, err := UnpackBytes(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+func TestUnpackBytesSkipsNoOp(t *testing.T) {
+	t.Log("UnpackBytes() should skip -128 headers written by other encoders.")
+	result, err := UnpackBytes([]byte{0x80, 0xFF, 0x41, 0x80})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x41, 0x41}, result)
+}
+
+func TestBytesRoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		[]byte("\xff\xfe\x00\x00\x00\x00not utf-8 \xc3\x28"),
+		bytes.Repeat([]byte{0xFF, 0xFF, 0x00}, 100),
+		append(bytes.Repeat([]byte{0x00}, 1000), 0x01, 0x01),
+	}
+	for _, input := range inputs {
+		t.Logf("UnpackBytes(CompressBytes()) should return % X", input)
+		result, err := UnpackBytes(CompressBytes(input))
+		assert.NoError(t, err)
+		assert.Equal(t, input, result)
+	}
+}
+
+func TestUnpackBytesFails(t *testing.T) {
+	for _, input := range [][]byte{{0x02, 0x41}, {0xFD}} {
+		t.Logf("UnpackBytes() should return an error when the input is % X", input)
+		_, err := UnpackBytes(input)
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+}
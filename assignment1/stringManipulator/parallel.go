@@ -0,0 +1,224 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"sync"
+	"unicode/utf8"
+)
+
+// Chunked layout:
+//
+//	magic    4 bytes  ChunkedMagic
+//	version  1 byte   ChunkedVersion
+//	codec    1 byte   ID of the codec that encoded every chunk
+//	count    uvarint  number of chunks
+//	index    for each chunk, the uvarint original length and the uvarint encoded length
+//	payload  the encoded chunks, back to back
+const (
+	ChunkedMagic     = "\x89RLC"
+	ChunkedVersion   = 1
+	DefaultChunkSize = 1 << 20
+)
+
+// ParallelOptions configures CompressParallel and UnpackParallel.
+// The zero value encodes 1 MiB chunks with RLE on runtime.GOMAXPROCS(0) workers.
+type ParallelOptions struct {
+	ChunkSize int   // bytes of input per chunk, rounded down to a rune boundary; 0 means DefaultChunkSize
+	Workers   int   // goroutines encoding or decoding chunks; 0 means runtime.GOMAXPROCS(0)
+	Codec     Codec // codec encoding each chunk; nil means RLE. Ignored by UnpackParallel.
+}
+
+// withDefaults fills in the zero fields of opts and validates the rest
+func (opts ParallelOptions) withDefaults() (ParallelOptions, error) {
+	if opts.ChunkSize < 0 || opts.Workers < 0 {
+		return opts, ErrInvalidOptions
+	}
+	if opts.ChunkSize == 0 {
+		opts.ChunkSize = DefaultChunkSize
+	}
+	if opts.Workers == 0 {
+		opts.Workers = runtime.GOMAXPROCS(0)
+	}
+	if opts.Codec == nil {
+		opts.Codec = RLE
+	}
+	return opts, nil
+}
+
+// CompressParallel splits src into chunks, encodes them concurrently and joins them behind a chunk index.
+// The output depends only on src, the chunk size and the codec, never on the number of workers.
+func CompressParallel(src []byte, opts ParallelOptions) ([]byte, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := splitChunks(src, opts.ChunkSize)
+	encoded := make([][]byte, len(chunks))
+	err = runChunks(len(chunks), opts.Workers, func(i int) error {
+		var err error
+		encoded[i], err = opts.Codec.Encode(chunks[i])
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var varint [binary.MaxVarintLen64]byte
+	putUvarint := func(dst []byte, v int) []byte {
+		return append(dst, varint[:binary.PutUvarint(varint[:], uint64(v))]...)
+	}
+	size := len(ChunkedMagic) + 2 + binary.MaxVarintLen64*(1+2*len(chunks))
+	for _, e := range encoded {
+		size += len(e)
+	}
+	blob := make([]byte, 0, size)
+	blob = append(blob, ChunkedMagic...)
+	blob = append(blob, ChunkedVersion, opts.Codec.ID())
+	blob = putUvarint(blob, len(chunks))
+	for i := range chunks {
+		blob = putUvarint(blob, len(chunks[i]))
+		blob = putUvarint(blob, len(encoded[i]))
+	}
+	for _, e := range encoded {
+		blob = append(blob, e...)
+	}
+	return blob, nil
+}
+
+// UnpackParallel reads the chunk index written by CompressParallel and decodes the chunks concurrently
+func UnpackParallel(blob []byte, opts ParallelOptions) ([]byte, error) {
+	opts, err := opts.withDefaults()
+	if err != nil {
+		return nil, err
+	}
+
+	if !bytes.HasPrefix(blob, []byte(ChunkedMagic)) {
+		return nil, ErrNotContainer
+	}
+	if len(blob) < len(ChunkedMagic)+2 {
+		return nil, ErrTruncated
+	}
+	if blob[4] != ChunkedVersion {
+		return nil, ErrUnsupportedVersion
+	}
+	c, ok := CodecByID(blob[5])
+	if !ok {
+		return nil, ErrUnknownCodec
+	}
+
+	rest := blob[len(ChunkedMagic)+2:]
+	uvarint := func() (int, bool) {
+		v, k := binary.Uvarint(rest)
+		if k <= 0 || v > uint64(maxInt) {
+			return 0, false
+		}
+		rest = rest[k:]
+		return int(v), true
+	}
+
+	// Every chunk takes at least two index bytes, which bounds the count before anything is allocated
+	count, ok := uvarint()
+	if !ok || count > len(rest)/2 {
+		return nil, fmt.Errorf("%w: chunk index is truncated", ErrMalformed)
+	}
+	rawLens := make([]int, count)
+	encLens := make([]int, count)
+	total, payloadLen := 0, 0
+	for i := 0; i < count; i++ {
+		raw, ok1 := uvarint()
+		n, ok2 := uvarint()
+		if !ok1 || !ok2 || raw > maxInt-total || n > maxInt-payloadLen {
+			return nil, fmt.Errorf("%w: chunk index is truncated", ErrMalformed)
+		}
+		rawLens[i], encLens[i] = raw, n
+		total += raw
+		payloadLen += n
+	}
+	if payloadLen != len(rest) {
+		return nil, fmt.Errorf("%w: chunk index does not match the payload", ErrMalformed)
+	}
+
+	// The index gives every chunk's offset up front, so the chunks can be decoded in any order
+	payloads := make([][]byte, count)
+	for i, n := range encLens {
+		payloads[i], rest = rest[:n], rest[n:]
+	}
+
+	decoded := make([][]byte, count)
+	err = runChunks(count, opts.Workers, func(i int) error {
+		data, err := decodeLimit(c, payloads[i], rawLens[i])
+		if err != nil {
+			return err
+		}
+		if len(data) != rawLens[i] {
+			return ErrLength
+		}
+		decoded[i] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	dst := make([]byte, 0, total)
+	for _, d := range decoded {
+		dst = append(dst, d...)
+	}
+	return dst, nil
+}
+
+// splitChunks cuts src into pieces of at most size bytes, moving each cut back to the start of a rune.
+// A cut only falls inside a rune when no rune starts in the last utf8.UTFMax bytes of the chunk.
+func splitChunks(src []byte, size int) [][]byte {
+	chunks := make([][]byte, 0, len(src)/size+1)
+	for len(src) > size {
+		end := size
+		for back := 0; back < utf8.UTFMax && end-back > 0; back++ {
+			if utf8.RuneStart(src[end-back]) {
+				end -= back
+				break
+			}
+		}
+		chunks = append(chunks, src[:end])
+		src = src[end:]
+	}
+	if len(src) > 0 {
+		chunks = append(chunks, src)
+	}
+	return chunks
+}
+
+// runChunks calls fn for every index below n on a pool of workers and returns the error of the lowest failing index
+func runChunks(n, workers int, fn func(i int) error) error {
+	if workers > n {
+		workers = n
+	}
+	errs := make([]error, n)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
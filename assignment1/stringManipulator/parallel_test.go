@@ -0,0 +1,131 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelRoundTrip(t *testing.T) {
+	for _, c := range Codecs() {
+		for _, chunkSize := range []int{1, 7, 64, 0} {
+			for _, input := range codecInputs {
+				t.Logf("%s: UnpackParallel(CompressParallel()) should return the %d byte input in %d byte chunks.", c.Name(), len(input), chunkSize)
+				opts := ParallelOptions{ChunkSize: chunkSize, Codec: c}
+				blob, err := CompressParallel(input, opts)
+				assert.NoError(t, err)
+				result, err := UnpackParallel(blob, opts)
+				assert.NoError(t, err)
+				assert.Equal(t, string(input), string(result))
+			}
+		}
+	}
+}
+
+func TestParallelDeterministic(t *testing.T) {
+	t.Log("CompressParallel() should give the same output whatever the number of workers.")
+	input := bytes.Repeat([]byte("aaaa€€€€bbbb\xff\xff"), 5000)
+	expected, err := CompressParallel(input, ParallelOptions{ChunkSize: 1000, Workers: 1})
+	assert.NoError(t, err)
+	for _, workers := range []int{2, 3, 16, 0} {
+		blob, err := CompressParallel(input, ParallelOptions{ChunkSize: 1000, Workers: workers})
+		assert.NoError(t, err)
+		assert.Equal(t, expected, blob)
+	}
+}
+
+func TestParallelLayout(t *testing.T) {
+	t.Log("CompressParallel() should write the magic, version, codec ID and chunk index before the chunks.")
+	blob, err := CompressParallel([]byte("aaaabb"), ParallelOptions{ChunkSize: 4})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\x89RLC\x01\x01\x02\x04\x04\x02\x04\\1a4\\1b2"), blob)
+}
+
+func TestSplitChunks(t *testing.T) {
+	t.Log("splitChunks() should only cut between runes.")
+	input := []byte(strings.Repeat("a€😀", 100))
+	for size := 1; size < 20; size++ {
+		chunks := splitChunks(input, size)
+		assert.Equal(t, input, bytes.Join(chunks, nil))
+		for _, chunk := range chunks {
+			assert.True(t, len(chunk) <= size)
+			if size >= utf8.UTFMax {
+				assert.True(t, utf8.Valid(chunk), "chunk %q of size %d", chunk, size)
+			}
+		}
+	}
+}
+
+func TestUnpackParallelFails(t *testing.T) {
+	blob, err := CompressParallel([]byte(strings.Repeat("abc", 100)), ParallelOptions{ChunkSize: 100, Codec: LZ77})
+	assert.NoError(t, err)
+	corrupt := func(i int, b byte) []byte {
+		c := append([]byte(nil), blob...)
+		c[i] = b
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		input    []byte
+		expected error
+	}{
+		{"not chunked", []byte("aaaa"), ErrNotContainer},
+		{"truncated header", blob[:5], ErrTruncated},
+		{"newer version", corrupt(4, 2), ErrUnsupportedVersion},
+		{"unknown codec", corrupt(5, 0xee), ErrUnknownCodec},
+		{"truncated index", blob[:9], ErrMalformed},
+		{"truncated payload", blob[:len(blob)-1], ErrMalformed},
+		{"wrong length", corrupt(7, 99), ErrLength},
+	}
+	for _, test := range tests {
+		t.Logf("UnpackParallel() should return %v when the input has a %s.", test.expected, test.name)
+		_, err := UnpackParallel(test.input, ParallelOptions{})
+		assert.True(t, errors.Is(err, test.expected), "got %v", err)
+	}
+
+	t.Log("UnpackParallel() should stop decoding a chunk once it expands past the length in the index.")
+	payload := "\\1a100000000"
+	bomb := append([]byte(ChunkedMagic), ChunkedVersion, RLEID, 1, 4, byte(len(payload)))
+	_, err = UnpackParallel(append(bomb, payload...), ParallelOptions{})
+	assert.True(t, errors.Is(err, ErrMalformed), "got %v", err)
+
+	t.Log("CompressParallel() and UnpackParallel() should reject negative options.")
+	_, err = CompressParallel(nil, ParallelOptions{ChunkSize: -1})
+	assert.Equal(t, ErrInvalidOptions, err)
+	_, err = UnpackParallel(blob, ParallelOptions{Workers: -1})
+	assert.Equal(t, ErrInvalidOptions, err)
+}
+
+func BenchmarkCompressParallel(b *testing.B) {
+	input := bytes.Repeat([]byte(benchmarkInputs[0].input), 64)
+	for _, workers := range []int{1, 0} {
+		name := "GOMAXPROCS"
+		if workers == 1 {
+			name = "Serial"
+		}
+		b.Run(name, func(b *testing.B) {
+			b.SetBytes(int64(len(input)))
+			for i := 0; i < b.N; i++ {
+				CompressParallel(input, ParallelOptions{ChunkSize: 1 << 16, Workers: workers})
+			}
+		})
+	}
+}
+
+func TestUnpackParallelLarge(t *testing.T) {
+	if testing.Short() {
+		t.Skip("decodes more than DefaultMaxOutput bytes")
+	}
+	t.Log("UnpackParallel() should return a chunk longer than DefaultMaxOutput, whose length the index records.")
+	input := bytes.Repeat([]byte("a"), DefaultMaxOutput+1)
+	blob, err := CompressParallel(input, ParallelOptions{ChunkSize: len(input), Codec: RLE})
+	assert.NoError(t, err)
+	result, err := UnpackParallel(blob, ParallelOptions{})
+	assert.NoError(t, err)
+	assert.True(t, bytes.Equal(input, result), "got %d bytes", len(result))
+}
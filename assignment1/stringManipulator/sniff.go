@@ -0,0 +1,136 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Kind identifies what Sniff finds data to be: one of the encodings written by this package, or plain text
+type Kind int
+
+const (
+	// KindPlain is text that is not encoded at all
+	KindPlain Kind = iota
+	// KindLegacy is the output of Compress
+	KindLegacy
+	// KindEscaped is the output of CompressEscaped, or of CompressWith with FormatEscaped and the default Options otherwise
+	KindEscaped
+	// KindTokens is the output of CompressTokens
+	KindTokens
+	// KindContainer is the output of Seal
+	KindContainer
+	// KindChunked is the output of CompressParallel
+	KindChunked
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindPlain:
+		return "plain"
+	case KindLegacy:
+		return "legacy"
+	case KindEscaped:
+		return "escaped"
+	case KindTokens:
+		return "tokens"
+	case KindContainer:
+		return "container"
+	case KindChunked:
+		return "chunked"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// ErrAmbiguous is returned by DecodeAuto for data that is as likely to be legacy output as plain text
+var ErrAmbiguous = errors.New("stringManipulator: input may be legacy output or plain text")
+
+// Confidences reported by Sniff
+const (
+	sniffCertain   = 1.0 // a magic number or a header that also decodes
+	sniffEscaped   = 0.9 // an escaped header that decodes, which plain text rarely starts with
+	sniffAmbiguous = 0.5 // legacy output with a single count, such as "h2o", which is as likely to be plain text
+)
+
+// Sniff guesses what data is: the output of Seal, CompressParallel, CompressTokens, CompressEscaped or Compress,
+// or plain text. It returns how confident the guess is, between 0.5 and 1. The rules are tried in order:
+//
+//   - ContainerMagic and ChunkedMagic identify KindContainer and KindChunked.
+//   - TokenHeader identifies KindTokens when the rest decodes.
+//   - An escaped header with DefaultEscape identifies KindEscaped when the rest decodes with the default Options.
+//     Output written with another escape rune, unit or count encoding is not recognised.
+//   - Valid UTF-8 that UnpackStrict accepts and that Compress writes exactly, with at least one count, is KindLegacy.
+//     The confidence grows with the number of counts; with a single count it is 0.5, as the data is as likely to be plain text.
+//   - Anything else is KindPlain. Text without digits unpacks to itself, so it is reported as plain as well.
+//
+// Sniff decodes data to check it, so it takes as long as decoding.
+func Sniff(data []byte) (Kind, float64) {
+	k, confidence, _ := sniff(data)
+	return k, confidence
+}
+
+// sniff implements Sniff. For the kinds it had to decode, it also returns the decoded data.
+func sniff(data []byte) (Kind, float64, []byte) {
+	switch {
+	case bytes.HasPrefix(data, []byte(ContainerMagic)):
+		return KindContainer, sniffCertain, nil
+	case bytes.HasPrefix(data, []byte(ChunkedMagic)):
+		return KindChunked, sniffCertain, nil
+	}
+
+	s := string(data)
+	if len(s) > len(TokenHeader) && s[:len(TokenHeader)] == TokenHeader {
+		if out, err := UnpackTokens(s); err == nil {
+			return KindTokens, sniffCertain, []byte(out)
+		}
+	}
+	if h, err := ParseEscapedHeader(s); err == nil && h.Escape == DefaultEscape {
+		if out, err := UnpackWith(s, Options{Format: FormatEscaped}); err == nil {
+			return KindEscaped, sniffEscaped, []byte(out)
+		}
+	}
+
+	if counts, out := legacyCounts(s); counts > 0 {
+		return KindLegacy, float64(counts) / float64(counts+1), []byte(out)
+	}
+	return KindPlain, sniffCertain, data
+}
+
+// legacyCounts returns the number of counts in s and s unpacked when s is exactly what Compress writes for some input,
+// or -1 otherwise. Plain text with numbers rarely qualifies, since a count is never followed by the character it repeats.
+func legacyCounts(s string) (int, string) {
+	if !utf8.ValidString(s) {
+		return -1, ""
+	}
+	out, err := UnpackStrict(s)
+	if err != nil || Compress(out) != s {
+		return -1, ""
+	}
+	counts := 0
+	for i := 0; i < len(s); i++ {
+		if isDigit(rune(s[i])) {
+			counts++
+		}
+	}
+	return counts, out
+}
+
+// DecodeAuto decodes data as what Sniff finds it to be. Plain text is returned unchanged.
+// Legacy output with a single count, which Sniff reports with confidence 0.5, returns ErrAmbiguous
+// rather than a guess. Use the decoder of the format instead when it is known.
+func DecodeAuto(data []byte) ([]byte, Kind, error) {
+	k, confidence, out := sniff(data)
+	switch {
+	case k == KindContainer:
+		out, err := Open(data)
+		return out, k, err
+	case k == KindChunked:
+		out, err := UnpackParallel(data, ParallelOptions{})
+		return out, k, err
+	case confidence == sniffAmbiguous:
+		return nil, k, ErrAmbiguous
+	}
+	return out, k, nil
+}
@@ -0,0 +1,114 @@
+package stringManipulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSniff(t *testing.T) {
+	sealed, err := Seal(Huffman, []byte("aaaaaaaaaa"))
+	assert.NoError(t, err)
+	chunked, err := CompressParallel([]byte("aaaaaaaaaa"), ParallelOptions{})
+	assert.NoError(t, err)
+	tokens, err := CompressTokens("ERROR ERROR ERROR", Whitespace)
+	assert.NoError(t, err)
+	escaped, err := CompressEscaped("aaaa1111", 0)
+	assert.NoError(t, err)
+
+	tests := []struct {
+		input      string
+		kind       Kind
+		confidence float64
+	}{
+		{"", KindPlain, 1},
+		{string(sealed), KindContainer, 1},
+		{string(chunked), KindChunked, 1},
+		{tokens, KindTokens, 1},
+		{escaped, KindEscaped, 0.9},
+		{"a6€5c3d3a2 ef2gj9j3", KindLegacy, 8.0 / 9},
+		{"a2b3", KindLegacy, 2.0 / 3},
+		{"h2o", KindLegacy, 0.5},
+		{"hello world", KindPlain, 1},
+		{"page 3 of 4", KindPlain, 1},
+		{"room 42", KindPlain, 1},
+		{"a1b2", KindPlain, 1},
+		{"a3a", KindPlain, 1},
+		{"\\1a", KindEscaped, 0.9},
+		{"\\1a\\", KindPlain, 1},
+		{"~1a4", KindPlain, 1},
+		{"\\T1a|", KindPlain, 1},
+		{"\xff2", KindPlain, 1},
+	}
+	for _, test := range tests {
+		t.Logf("Sniff(%q) should return %v with confidence %.2f.", test.input, test.kind, test.confidence)
+		kind, confidence := Sniff([]byte(test.input))
+		assert.Equal(t, test.kind, kind)
+		assert.InDelta(t, test.confidence, confidence, 1e-9)
+	}
+}
+
+func TestDecodeAuto(t *testing.T) {
+	inputs := []string{"", "aaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj", "aaaa1111", "ERROR ERROR ERROR", "plain text, 42 of it"}
+	encoders := []struct {
+		kind   Kind
+		encode func(string) ([]byte, error)
+	}{
+		{KindContainer, func(s string) ([]byte, error) { return Seal(LZ77, []byte(s)) }},
+		{KindChunked, func(s string) ([]byte, error) { return CompressParallel([]byte(s), ParallelOptions{ChunkSize: 4}) }},
+		{KindTokens, func(s string) ([]byte, error) {
+			out, err := CompressTokens(s, Whitespace)
+			return []byte(out), err
+		}},
+		{KindEscaped, func(s string) ([]byte, error) {
+			out, err := CompressEscaped(s, 0)
+			return []byte(out), err
+		}},
+	}
+	for _, input := range inputs[1:] {
+		for _, e := range encoders {
+			t.Logf("DecodeAuto() should detect %v and decode %q.", e.kind, input)
+			encoded, err := e.encode(input)
+			assert.NoError(t, err)
+			out, kind, err := DecodeAuto(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, e.kind, kind)
+			assert.Equal(t, input, string(out))
+		}
+	}
+
+	t.Log("DecodeAuto() should unpack legacy output and return plain text unchanged.")
+	out, kind, err := DecodeAuto([]byte(Compress(inputs[1])))
+	assert.NoError(t, err)
+	assert.Equal(t, KindLegacy, kind)
+	assert.Equal(t, inputs[1], string(out))
+	for _, input := range inputs {
+		out, _, err := DecodeAuto([]byte(input))
+		assert.NoError(t, err)
+		assert.Equal(t, input, string(out))
+	}
+
+	t.Log("DecodeAuto() should refuse to guess whether legacy output with a single count is plain text.")
+	for _, input := range []string{"hho", "aaab", "hello"} {
+		_, kind, err := DecodeAuto([]byte(Compress(input)))
+		assert.Equal(t, KindLegacy, kind)
+		assert.Equal(t, ErrAmbiguous, err)
+	}
+
+	t.Log("DecodeAuto() should report errors of the detected format.")
+	sealed, err := Seal(RLE, []byte("abc"))
+	assert.NoError(t, err)
+	sealed[len(sealed)-1] ^= 1
+	_, kind, err = DecodeAuto(sealed)
+	assert.Equal(t, KindContainer, kind)
+	assert.True(t, errors.Is(err, ErrChecksum))
+}
+
+func TestKindString(t *testing.T) {
+	t.Log("Kind should have a name for every kind Sniff reports.")
+	for k, name := range []string{"plain", "legacy", "escaped", "tokens", "container", "chunked"} {
+		assert.Equal(t, name, Kind(k).String())
+	}
+	assert.Equal(t, "Kind(9)", Kind(9).String())
+}
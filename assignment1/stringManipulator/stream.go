@@ -0,0 +1,201 @@
+package stringManipulator
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// maxLegacyRun is the longest run Compress writes before starting a new one
+const maxLegacyRun = 9
+
+// ErrClosed is returned when writing to an Encoder after Close
+var ErrClosed = errors.New("stringManipulator: write to closed Encoder")
+
+// Encoder compresses everything written to it and writes the result to an underlying io.Writer.
+// The output is identical to Compress of the concatenated input, but only the current run is held in memory.
+type Encoder struct {
+	w       *bufio.Writer
+	pending []byte // incomplete UTF-8 sequence carried over from the previous Write
+	prev    [utf8.UTFMax]byte
+	prevLen int
+	count   int
+	closed  bool
+	err     error
+}
+
+// NewEncoder returns an Encoder writing to w. Close must be called to flush the final run.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{
+		w:       bufio.NewWriter(w),
+		pending: make([]byte, 0, 2*utf8.UTFMax),
+	}
+}
+
+// Write compresses p. Runs and multi-byte characters may span several calls to Write.
+func (e *Encoder) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, ErrClosed
+	}
+	if e.err != nil {
+		return 0, e.err
+	}
+	n := len(p)
+
+	// Finish the character split across the previous buffer boundary
+	if k := len(e.pending); k > 0 {
+		head := p
+		if len(head) > utf8.UTFMax {
+			head = head[:utf8.UTFMax]
+		}
+		buf := append(e.pending, head...)
+		i := 0
+		for i < k {
+			if !utf8.FullRune(buf[i:]) {
+				e.pending = append(e.pending[:0], buf[i:]...)
+				return n, nil
+			}
+			_, size := utf8.DecodeRune(buf[i:])
+			e.add(buf[i : i+size])
+			i += size
+		}
+		p = p[i-k:]
+		e.pending = e.pending[:0]
+	}
+
+	for i := 0; i < len(p); {
+		if !utf8.FullRune(p[i:]) {
+			e.pending = append(e.pending[:0], p[i:]...)
+			break
+		}
+		_, size := utf8.DecodeRune(p[i:])
+		e.add(p[i : i+size])
+		i += size
+	}
+
+	return n, e.err
+}
+
+// Close flushes the final run to the underlying writer. It does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return e.err
+	}
+	e.closed = true
+
+	// Bytes left over at the end of input can never complete, so they are written one at a time
+	for i := 0; i < len(e.pending); {
+		_, size := utf8.DecodeRune(e.pending[i:])
+		e.add(e.pending[i : i+size])
+		i += size
+	}
+	e.pending = e.pending[:0]
+	e.flushRun()
+
+	if e.err == nil {
+		e.err = e.w.Flush()
+	}
+	return e.err
+}
+
+// add appends a single character to the current run
+func (e *Encoder) add(char []byte) {
+	if e.count > 0 && e.count < maxLegacyRun && bytes.Equal(char, e.prev[:e.prevLen]) {
+		e.count++
+		return
+	}
+	e.flushRun()
+	e.prevLen = copy(e.prev[:], char)
+	e.count = 1
+}
+
+// flushRun writes the current run to the underlying writer
+func (e *Encoder) flushRun() {
+	if e.count == 0 || e.err != nil {
+		return
+	}
+	if _, err := e.w.Write(e.prev[:e.prevLen]); err != nil {
+		e.err = err
+		return
+	}
+	if e.count > 1 {
+		if err := e.w.WriteByte(byte('0' + e.count)); err != nil {
+			e.err = err
+			return
+		}
+	}
+	e.count = 0
+}
+
+// Decoder reads compressed data from an underlying io.Reader and returns the unpacked text.
+// The output is identical to Unpack of the whole input.
+type Decoder struct {
+	r       *bufio.Reader
+	prev    [utf8.UTFMax]byte
+	prevLen int
+	out     []byte // decoded bytes not yet returned by Read
+	off     int
+	err     error
+}
+
+// NewDecoder returns a Decoder reading from r
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:   bufio.NewReader(r),
+		out: make([]byte, 0, maxLegacyRun*utf8.UTFMax+utf8.UTFMax),
+	}
+}
+
+// Read reads up to len(p) bytes of unpacked text into p
+func (d *Decoder) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if d.off < len(d.out) {
+			c := copy(p[n:], d.out[d.off:])
+			n += c
+			d.off += c
+			continue
+		}
+		if d.err != nil {
+			break
+		}
+		d.out, d.off = d.out[:0], 0
+		d.step()
+	}
+	if n == 0 && d.err != nil {
+		return 0, d.err
+	}
+	return n, nil
+}
+
+// step decodes the next character of input into d.out
+func (d *Decoder) step() {
+	b, err := d.r.Peek(utf8.UTFMax)
+	if len(b) == 0 || (err != nil && err != io.EOF && !utf8.FullRune(b)) {
+		if err == io.EOF {
+			d.out = append(d.out, d.prev[:d.prevLen]...)
+			d.prevLen = 0
+		}
+		d.err = err
+		return
+	}
+	_, size := utf8.DecodeRune(b)
+	char := b[:size]
+
+	if n, err := strconv.Atoi(string(char)); err == nil && d.prevLen > 0 {
+		for j := 0; j < n; j++ {
+			d.out = append(d.out, d.prev[:d.prevLen]...)
+		}
+		d.prevLen = 0
+	} else {
+		d.out = append(d.out, d.prev[:d.prevLen]...)
+		d.prevLen = copy(d.prev[:], char)
+	}
+
+	if _, err := d.r.Discard(size); err != nil {
+		d.err = err
+	}
+}
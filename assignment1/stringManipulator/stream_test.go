@@ -0,0 +1,77 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var streamInputs = []string{
+	"",
+	"a",
+	"aaaaaa€€€€€cccdddaa effgjjjjjjjjjjjj",
+	strings.Repeat("a", 1000),
+	strings.Repeat("€", 25) + strings.Repeat("😀", 11),
+	"11111111111111111111",
+	"a9999b0c",
+	"\xe2\x82\xe2\x82\xe2\x82\xac\xff\xff",
+	"trailing partial \xf0\x9f\x98",
+}
+
+// encodeInChunks writes s to an Encoder size bytes at a time
+func encodeInChunks(t *testing.T, s string, size int) string {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	for i := 0; i < len(s); i += size {
+		j := i + size
+		if j > len(s) {
+			j = len(s)
+		}
+		n, err := enc.Write([]byte(s[i:j]))
+		assert.NoError(t, err)
+		assert.Equal(t, j-i, n)
+	}
+	assert.NoError(t, enc.Close())
+	return buf.String()
+}
+
+func TestEncoder(t *testing.T) {
+	for _, input := range streamInputs {
+		expectedResult := Compress(input)
+		for _, size := range []int{1, 2, 3, 5, 4096} {
+			t.Logf("Encoder should return %q when the input %q is written %d bytes at a time", expectedResult, input, size)
+			assert.Equal(t, expectedResult, encodeInChunks(t, input, size))
+		}
+	}
+}
+
+func TestEncoderWriteAfterClose(t *testing.T) {
+	t.Log("Write() should return ErrClosed after Close().")
+	enc := NewEncoder(ioutil.Discard)
+	assert.NoError(t, enc.Close())
+	_, err := enc.Write([]byte("a"))
+	assert.Equal(t, ErrClosed, err)
+}
+
+func TestDecoder(t *testing.T) {
+	inputs := append([]string{"a99999", "9a", "€0b3"}, streamInputs...)
+	for _, input := range inputs {
+		compressed := Compress(input)
+		for _, encoded := range []string{input, compressed} {
+			expectedResult := Unpack(encoded)
+			t.Logf("Decoder should return %q when the input is %q", expectedResult, encoded)
+
+			b, err := ioutil.ReadAll(NewDecoder(strings.NewReader(encoded)))
+			assert.NoError(t, err)
+			assert.Equal(t, expectedResult, string(b))
+
+			b, err = ioutil.ReadAll(iotest.OneByteReader(NewDecoder(iotest.OneByteReader(strings.NewReader(encoded)))))
+			assert.NoError(t, err)
+			assert.Equal(t, expectedResult, string(b))
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package stringManipulator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SyntaxError describes where and why encoded input could not be decoded
+type SyntaxError struct {
+	Offset   int    // byte offset of the problem in the input
+	Rune     int    // rune index of the problem in the input
+	Found    string // text found at Offset, empty at the end of input
+	Expected string // description of what was expected at Offset
+}
+
+// Error returns the error message
+func (e *SyntaxError) Error() string {
+	found := "end of input"
+	if e.Found != "" {
+		found = fmt.Sprintf("%q", e.Found)
+	}
+	return fmt.Sprintf("stringManipulator: invalid input at byte %d (rune %d): found %s, expected %s", e.Offset, e.Rune, found, e.Expected)
+}
+
+// Unwrap allows errors.Is(err, ErrMalformed) to match every SyntaxError
+func (e *SyntaxError) Unwrap() error {
+	return ErrMalformed
+}
+
+// newSyntaxError returns a SyntaxError for the problem at offset in s
+func newSyntaxError(s string, offset int, expected string) *SyntaxError {
+	var found string
+	if offset < len(s) {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		found = s[offset : offset+size]
+	}
+	return &SyntaxError{
+		Offset:   offset,
+		Rune:     utf8.RuneCountInString(s[:offset]),
+		Found:    found,
+		Expected: expected,
+	}
+}
+
+// UnpackStrict reverses Compress but rejects input that Unpack would silently misread.
+// Every digit must be a count between 2 and 9 directly following a character, and the input must be valid UTF-8.
+// The returned error is a *SyntaxError.
+func UnpackStrict(s string) (string, error) {
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return "", newSyntaxError(s, i, "valid UTF-8")
+		}
+		if isDigit(r) {
+			return "", newSyntaxError(s, i, "a character before the count")
+		}
+		char := s[i : i+size]
+		i += size
+
+		count := 1
+		if i < len(s) && isDigit(rune(s[i])) {
+			if s[i] < '2' {
+				return "", newSyntaxError(s, i, "a count between 2 and 9")
+			}
+			count = int(s[i] - '0')
+			i++
+		}
+		for j := 0; j < count; j++ {
+			b.WriteString(char)
+		}
+	}
+	return b.String(), nil
+}
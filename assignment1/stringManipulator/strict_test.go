@@ -0,0 +1,66 @@
+package stringManipulator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnpackStrict(t *testing.T) {
+	for i := 0; i < 4; i++ {
+		input, expectedResult := tests[i][1], tests[i][0]
+		t.Logf("UnpackStrict() should return %s when the input is %s", expectedResult, input)
+		result, err := UnpackStrict(input)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedResult, result)
+	}
+}
+
+var strictErrorTests = []struct {
+	input    string
+	expected SyntaxError
+}{
+	{
+		"9a",
+		SyntaxError{Offset: 0, Rune: 0, Found: "9", Expected: "a character before the count"},
+	},
+	{
+		"€€3a99",
+		SyntaxError{Offset: 9, Rune: 5, Found: "9", Expected: "a character before the count"},
+	},
+	{
+		"ab1",
+		SyntaxError{Offset: 2, Rune: 2, Found: "1", Expected: "a count between 2 and 9"},
+	},
+	{
+		"a0",
+		SyntaxError{Offset: 1, Rune: 1, Found: "0", Expected: "a count between 2 and 9"},
+	},
+	{
+		"é2\xff",
+		SyntaxError{Offset: 3, Rune: 2, Found: "\xff", Expected: "valid UTF-8"},
+	},
+}
+
+func TestUnpackStrictFails(t *testing.T) {
+	for _, test := range strictErrorTests {
+		t.Logf("UnpackStrict() should return %v when the input is %q", test.expected, test.input)
+		_, err := UnpackStrict(test.input)
+
+		var syntaxErr *SyntaxError
+		if assert.True(t, errors.As(err, &syntaxErr)) {
+			assert.Equal(t, test.expected, *syntaxErr)
+		}
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+}
+
+func TestSyntaxErrorMessage(t *testing.T) {
+	t.Log("Error() should describe the position, the text found and what was expected.")
+	_, err := UnpackStrict("ab1")
+	assert.EqualError(t, err, `stringManipulator: invalid input at byte 2 (rune 2): found "1", expected a count between 2 and 9`)
+
+	_, err = UnpackEscaped("\\1a\\")
+	assert.EqualError(t, err, `stringManipulator: invalid input at byte 4 (rune 4): found end of input, expected a character after the escape`)
+}
@@ -1,8 +1,8 @@
 package stringManipulator
 
 import (
-	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // Compress() compresses strings
@@ -12,28 +12,25 @@ func Compress(s string) string {
 		return s
 	}
 
-	// Split string to array for simplified iteration and preservation of special characters
-	var stringArr = strings.Split(s, "")
-	var compressedString string
-	var prev string
-	var count int
-	for i := 0; i < len(stringArr); i++ {
-		char := stringArr[i]
-		if char != prev || count >= 9 {
-			compressedString += prev
-			if count > 1 {
-				compressedString += strconv.Itoa(count)
-			}
-			prev, count = char, 0
+	// A run is never written longer than it is, so the output fits in len(s) bytes
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := 0; i < len(s); {
+		// Decode one character at a time, keeping invalid UTF-8 bytes as characters of their own
+		_, size := utf8.DecodeRuneInString(s[i:])
+		char := s[i : i+size]
+		count := 1
+		for i += size; i < len(s) && count < maxLegacyRun && strings.HasPrefix(s[i:], char) && runeLen(s[i:]) == size; i += size {
+			count++
+		}
+
+		b.WriteString(char)
+		if count > 1 {
+			b.WriteByte(byte('0' + count))
 		}
-		count++
-	}
-	compressedString += prev
-	if count > 1 {
-		compressedString += strconv.Itoa(count)
 	}
 
-	return compressedString
+	return b.String()
 }
 
 func Unpack(s string) string {
@@ -42,22 +39,39 @@ func Unpack(s string) string {
 		return s
 	}
 
-	var stringArr = strings.Split(s, "")
-	var unpackedString string
+	// Measure the output first so it is allocated exactly once
+	var b strings.Builder
+	b.Grow(unpackLegacy(s, nil))
+	unpackLegacy(s, &b)
+	return b.String()
+}
+
+// unpackLegacy writes the unpacked form of s to b and returns its length. A nil b only measures it.
+// A digit directly following a character is its count. Any other digit is a literal character.
+func unpackLegacy(s string, b *strings.Builder) int {
+	var n int
 	var prev string
-	for i := 0; i < len(stringArr); i++ {
-		char := stringArr[i]
-		n, err := strconv.Atoi(char)
-		if err == nil && prev != "" {
-			for j := 0; j < n; j++ {
-				unpackedString += prev
+	write := func(char string, count int) {
+		n += len(char) * count
+		if b != nil {
+			for j := 0; j < count; j++ {
+				b.WriteString(char)
 			}
+		}
+	}
+
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		char := s[i : i+size]
+		i += size
+		if size == 1 && isDigit(rune(char[0])) && prev != "" {
+			write(prev, int(char[0]-'0'))
 			prev = ""
 		} else {
-			unpackedString += prev
+			write(prev, 1)
 			prev = char
 		}
 	}
-	unpackedString += prev
-	return unpackedString
+	write(prev, 1)
+	return n
 }
@@ -1,6 +1,9 @@
 package stringManipulator
 
 import (
+	"math/rand"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -19,16 +22,12 @@ var tests = [][]string{
 		"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
 		"a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a9a5",
 	},
-	// TODO: escape sequences test(s)
+	// escape sequences: UnitEntity keeps sequences such as `\n` whole, see entity_test.go
 	{
 		"\n\n\n",
 		"\n3",
 	},
-	// TODO: entities test(s)
-	// {
-	// 	"&#39;&#39;&#39;",
-	// 	"&#39;&#39;&#39;",
-	// },
+	// entities: Unpack reads the digits of "&#39;" as counts, so UnitEntity keeps references whole, see entity_test.go
 	// TODO: numbers test(s)
 	{
 		"11111111111111111111",
@@ -42,7 +41,11 @@ var tests = [][]string{
 		"99999999999999999999",
 		"999992",
 	},
-	// TODO: emojis test(s)
+	// emojis: multi-rune sequences are covered by UnitGrapheme in grapheme_test.go
+	{
+		"😀😀😀🎉",
+		"😀3🎉",
+	},
 	// TODO: other special character tests
 }
 
@@ -83,3 +86,122 @@ func TestUnpackFails(t *testing.T) {
 		assert.Equal(t, expectedResult, result)
 	}
 }
+
+// compressReference and unpackReference are the original implementations of Compress and Unpack,
+// kept to check that the rewrite produces identical output
+func compressReference(s string) string {
+	if len(s) < 1 {
+		return s
+	}
+	var stringArr = strings.Split(s, "")
+	var compressedString string
+	var prev string
+	var count int
+	for i := 0; i < len(stringArr); i++ {
+		char := stringArr[i]
+		if char != prev || count >= 9 {
+			compressedString += prev
+			if count > 1 {
+				compressedString += strconv.Itoa(count)
+			}
+			prev, count = char, 0
+		}
+		count++
+	}
+	compressedString += prev
+	if count > 1 {
+		compressedString += strconv.Itoa(count)
+	}
+	return compressedString
+}
+
+func unpackReference(s string) string {
+	if len(s) < 1 {
+		return s
+	}
+	var stringArr = strings.Split(s, "")
+	var unpackedString string
+	var prev string
+	for i := 0; i < len(stringArr); i++ {
+		char := stringArr[i]
+		n, err := strconv.Atoi(char)
+		if err == nil && prev != "" {
+			for j := 0; j < n; j++ {
+				unpackedString += prev
+			}
+			prev = ""
+		} else {
+			unpackedString += prev
+			prev = char
+		}
+	}
+	unpackedString += prev
+	return unpackedString
+}
+
+// randomText returns a string of n characters drawn from alphabet, repeating each up to maxRun times
+func randomText(r *rand.Rand, alphabet []string, n, maxRun int) string {
+	var b strings.Builder
+	for i := 0; i < n; {
+		char, run := alphabet[r.Intn(len(alphabet))], 1+r.Intn(maxRun)
+		b.WriteString(strings.Repeat(char, run))
+		i += run
+	}
+	return b.String()
+}
+
+func TestMatchesReference(t *testing.T) {
+	t.Log("Compress() and Unpack() should return the same output as the original implementations.")
+	r := rand.New(rand.NewSource(1))
+	alphabet := []string{"a", "b", "0", "1", "9", "€", "😀", "\xff", "\xe2", "\x82", "\n"}
+	for i := 0; i < 2000; i++ {
+		input := randomText(r, alphabet, r.Intn(40), 12)
+		assert.Equal(t, compressReference(input), Compress(input), "Compress(%q)", input)
+		assert.Equal(t, unpackReference(input), Unpack(input), "Unpack(%q)", input)
+	}
+}
+
+func TestAllocations(t *testing.T) {
+	t.Log("Compress() and Unpack() should allocate only their result.")
+	input := benchmarkInputs[1].input
+	compressed := Compress(input)
+	assert.Equal(t, 1.0, testing.AllocsPerRun(10, func() { Compress(input) }))
+	assert.Equal(t, 1.0, testing.AllocsPerRun(10, func() { Unpack(compressed) }))
+}
+
+var benchmarkInputs = []struct {
+	name  string
+	input string
+}{
+	{"ASCII", randomText(rand.New(rand.NewSource(1)), strings.Split("abcdefghij klmnopqrstuvwxyz", ""), 1<<16, 12)},
+	{"MultiByte", randomText(rand.New(rand.NewSource(1)), []string{"€", "é", "😀", "ß", "中"}, 1<<16, 12)},
+	{"NoRuns", strings.Repeat("abcdefghijklmnopqrstuvwxyz", 1<<16/26)},
+	{"LongRun", strings.Repeat("a", 1<<16)},
+	{"Digits", strings.Repeat("1", 1<<16)},
+	{"InvalidUTF8", strings.Repeat("\xff\xe2\x82", 1<<16/3)},
+}
+
+func BenchmarkCompress(b *testing.B) {
+	for _, bm := range benchmarkInputs {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(bm.input)))
+			for i := 0; i < b.N; i++ {
+				Compress(bm.input)
+			}
+		})
+	}
+}
+
+func BenchmarkUnpack(b *testing.B) {
+	for _, bm := range benchmarkInputs {
+		compressed := Compress(bm.input)
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(compressed)))
+			for i := 0; i < b.N; i++ {
+				Unpack(compressed)
+			}
+		})
+	}
+}
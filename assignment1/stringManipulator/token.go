@@ -0,0 +1,232 @@
+package stringManipulator
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Splitter returns the byte offsets [start, end) of the separators in s, in order and without overlaps.
+// The text before, between and after the separators is the tokens.
+type Splitter func(s string) [][]int
+
+// Whitespace is a Splitter treating every run of Unicode white space as one separator
+func Whitespace(s string) [][]int {
+	var spans [][]int
+	start := -1
+	for i, r := range s {
+		switch {
+		case unicode.IsSpace(r) && start < 0:
+			start = i
+		case !unicode.IsSpace(r) && start >= 0:
+			spans = append(spans, []int{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, []int{start, len(s)})
+	}
+	return spans
+}
+
+// Delimiter returns a Splitter treating every instance of sep as a separator, like strings.Split
+func Delimiter(sep string) Splitter {
+	return func(s string) [][]int {
+		if sep == "" {
+			return nil
+		}
+		var spans [][]int
+		for i := 0; ; {
+			j := strings.Index(s[i:], sep)
+			if j < 0 {
+				return spans
+			}
+			spans = append(spans, []int{i + j, i + j + len(sep)})
+			i += j + len(sep)
+		}
+	}
+}
+
+// Regexp returns a Splitter treating every match of re as a separator. Empty matches are ignored.
+func Regexp(re *regexp.Regexp) Splitter {
+	return func(s string) [][]int {
+		var spans [][]int
+		for _, m := range re.FindAllStringIndex(s, -1) {
+			if m[0] < m[1] {
+				spans = append(spans, m)
+			}
+		}
+		return spans
+	}
+}
+
+// Token format: a header made of TokenHeader, then one entry per run of identical tokens.
+// A run of count tokens joined by the same separator is written as
+//
+//	token '*' count '*' join '|' sep '|'
+//
+// and a single token as
+//
+//	token '|' sep '|'
+//
+// where sep is the separator following the run, empty after the last one.
+// Backslash, '*' and '|' in tokens and separators are written with a backslash in front of them.
+const (
+	TokenVersion = 1
+	TokenHeader  = "\\T1"
+)
+
+// tokenEscape and the token format's delimiters
+const (
+	tokenEscape = '\\'
+	tokenCount  = '*'
+	tokenEnd    = '|'
+)
+
+// CompressTokens splits s into tokens with split and collapses consecutive identical tokens joined by identical separators.
+// It returns ErrInvalidOptions when split is nil or returns separators that are out of order, overlapping or out of range.
+func CompressTokens(s string, split Splitter) (string, error) {
+	if split == nil {
+		return "", ErrInvalidOptions
+	}
+	if len(s) < 1 {
+		return s, nil
+	}
+
+	// tokens[k] is followed by seps[k]; the last token has no separator after it
+	var tokens, seps []string
+	prev := 0
+	for _, span := range split(s) {
+		if len(span) != 2 || span[0] < prev || span[1] < span[0] || span[1] > len(s) {
+			return "", ErrInvalidOptions
+		}
+		tokens = append(tokens, s[prev:span[0]])
+		seps = append(seps, s[span[0]:span[1]])
+		prev = span[1]
+	}
+	tokens = append(tokens, s[prev:])
+
+	var b strings.Builder
+	b.Grow(len(s) + len(TokenHeader))
+	b.WriteString(TokenHeader)
+	for k := 0; k < len(tokens); {
+		// Empty tokens joined by empty separators are written one by one, as a count would expand to nothing
+		count := 1
+		for k+count < len(tokens) && len(tokens[k])+len(seps[k]) > 0 && tokens[k+count] == tokens[k] && seps[k+count-1] == seps[k] {
+			count++
+		}
+
+		writeTokenText(&b, tokens[k])
+		if count > 1 {
+			b.WriteByte(tokenCount)
+			b.WriteString(strconv.Itoa(count))
+			b.WriteByte(tokenCount)
+			writeTokenText(&b, seps[k])
+		}
+		b.WriteByte(tokenEnd)
+		if k += count; k <= len(seps) {
+			writeTokenText(&b, seps[k-1])
+		}
+		b.WriteByte(tokenEnd)
+	}
+	return b.String(), nil
+}
+
+// writeTokenText writes s with the token format's special characters escaped
+func writeTokenText(b *strings.Builder, s string) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case tokenEscape, tokenCount, tokenEnd:
+			b.WriteByte(tokenEscape)
+		}
+		b.WriteByte(s[i])
+	}
+}
+
+// UnpackTokens reverses CompressTokens. The returned error is a *SyntaxError,
+// including for output longer than DefaultMaxOutput.
+func UnpackTokens(s string) (string, error) {
+	if len(s) < 1 {
+		return s, nil
+	}
+	if !strings.HasPrefix(s, TokenHeader) {
+		return "", newSyntaxError(s, 0, "a token format header")
+	}
+
+	var b strings.Builder
+	b.Grow(len(s))
+	for i := len(TokenHeader); i < len(s); {
+		token, next, end, err := readTokenText(s, i)
+		if err != nil {
+			return "", err
+		}
+		i = next + 1
+
+		count, join := 1, ""
+		if end == tokenCount {
+			j := strings.IndexByte(s[i:], tokenCount)
+			if j < 0 {
+				return "", newSyntaxError(s, len(s), "a closing "+string(tokenCount))
+			}
+			count, err = strconv.Atoi(s[i : i+j])
+			if err != nil || count < 2 || s[i] < '1' || s[i] > '9' {
+				return "", newSyntaxError(s, i, "a count of at least 2")
+			}
+			if join, next, end, err = readTokenText(s, i+j+1); err != nil {
+				return "", err
+			}
+			if end != tokenEnd {
+				return "", newSyntaxError(s, next, "a "+string(tokenEnd)+" after the separator")
+			}
+			if len(token)+len(join) == 0 {
+				return "", newSyntaxError(s, i, "a non-empty token or separator before a count")
+			}
+			if count > (DefaultMaxOutput-b.Len())/(len(token)+len(join)) {
+				return "", newSyntaxError(s, i, "a run within the output limit of "+strconv.Itoa(DefaultMaxOutput)+" bytes")
+			}
+			i = next + 1
+		}
+
+		sep, next, end, err := readTokenText(s, i)
+		if err != nil {
+			return "", err
+		}
+		if end != tokenEnd {
+			return "", newSyntaxError(s, next, "a "+string(tokenEnd)+" after the separator")
+		}
+		if len(sep) > DefaultMaxOutput-b.Len()-count*len(token)-(count-1)*len(join) {
+			return "", newSyntaxError(s, i, "a separator within the output limit of "+strconv.Itoa(DefaultMaxOutput)+" bytes")
+		}
+		i = next + 1
+
+		b.WriteString(token)
+		for k := 1; k < count; k++ {
+			b.WriteString(join)
+			b.WriteString(token)
+		}
+		b.WriteString(sep)
+	}
+	return b.String(), nil
+}
+
+// readTokenText reads escaped text starting at s[i] up to the next unescaped '*' or '|'.
+// It returns the unescaped text, the offset of the delimiter and the delimiter itself.
+func readTokenText(s string, i int) (text string, next int, end byte, err error) {
+	var b strings.Builder
+	for ; i < len(s); i++ {
+		switch c := s[i]; c {
+		case tokenEscape:
+			i++
+			if i == len(s) || (s[i] != tokenEscape && s[i] != tokenCount && s[i] != tokenEnd) {
+				return "", i, 0, newSyntaxError(s, i, "an escaped \\, * or |")
+			}
+			b.WriteByte(s[i])
+		case tokenCount, tokenEnd:
+			return b.String(), i, c, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", i, 0, newSyntaxError(s, i, "a "+string(tokenEnd))
+}
@@ -0,0 +1,122 @@
+package stringManipulator
+
+import (
+	"errors"
+	"math/rand"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressTokens(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		split    Splitter
+		expected string
+	}{
+		{"empty", "", Whitespace, ""},
+		{"repeated words", "ERROR ERROR ERROR", Whitespace, `\T1ERROR*3* ||`},
+		{"log lines", "ERROR ERROR ERROR disk full\nWARN WARN\n", Whitespace, `\T1ERROR*3* | |disk| |full|` + "\n" + `|WARN*2* |` + "\n" + `|||`},
+		{"different separators", "a a\ta", Whitespace, `\T1a*2* |` + "\t" + `|a||`},
+		{"csv fields", "1,,,,x", Delimiter(","), `\T11|,|*3*,|,|x||`},
+		{"csv rows", "a,b\na,b\na,b\n", Delimiter("\n"), `\T1a,b*3*` + "\n|\n|||"},
+		{"regexp", "foo; foo;foo", Regexp(regexp.MustCompile(`; ?`)), `\T1foo*2*; |;|foo||`},
+		{"special characters", `a*b|c\`, Whitespace, `\T1a\*b\|c\\||`},
+	}
+	for _, test := range tests {
+		t.Logf("CompressTokens() should collapse the repeated tokens in %s.", test.name)
+		result, err := CompressTokens(test.input, test.split)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, result)
+
+		t.Logf("UnpackTokens() should restore %s.", test.name)
+		result, err = UnpackTokens(result)
+		assert.NoError(t, err)
+		assert.Equal(t, test.input, result)
+	}
+}
+
+func TestCompressTokensRoundTrip(t *testing.T) {
+	t.Log("UnpackTokens(CompressTokens()) should return any input with any splitter.")
+	r := rand.New(rand.NewSource(3))
+	alphabet := []string{"ERROR", "a", " ", "  ", ",", "\n", "*", "|", "\\", "€", "\xff"}
+	splitters := []Splitter{Whitespace, Delimiter(","), Delimiter("  "), Regexp(regexp.MustCompile(`[,|]+`))}
+	for i := 0; i < 500; i++ {
+		s := randomText(r, alphabet, 40, 5)
+		for _, split := range splitters {
+			compressed, err := CompressTokens(s, split)
+			assert.NoError(t, err)
+			result, err := UnpackTokens(compressed)
+			assert.NoError(t, err)
+			assert.Equal(t, s, result)
+		}
+	}
+}
+
+func TestCompressTokensInvalidSplitter(t *testing.T) {
+	t.Log("CompressTokens() should reject a nil splitter or separators out of order.")
+	_, err := CompressTokens("a b", nil)
+	assert.Equal(t, ErrInvalidOptions, err)
+	for _, spans := range [][][]int{{{2, 1}}, {{1, 2}, {0, 1}}, {{1, 4}}, {{1}}} {
+		spans := spans
+		_, err := CompressTokens("a b", func(string) [][]int { return spans })
+		assert.Equal(t, ErrInvalidOptions, err)
+	}
+}
+
+func TestCompressTokensEmptySeparators(t *testing.T) {
+	t.Log("CompressTokens() should not count empty tokens joined by empty separators.")
+	spans := [][]int{{0, 0}, {0, 0}, {0, 0}}
+	compressed, err := CompressTokens("ab", func(string) [][]int { return spans })
+	assert.NoError(t, err)
+	assert.Equal(t, `\T1||||||ab||`, compressed)
+	result, err := UnpackTokens(compressed)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", result)
+}
+
+func TestUnpackTokensFails(t *testing.T) {
+	tests := []struct {
+		input    string
+		offset   int
+		expected string
+	}{
+		{"ERROR*3* ||", 0, "a token format header"},
+		{`\T1a`, 4, "a |"},
+		{`\T1a|`, 5, "a |"},
+		{`\T1a*3`, 6, "a closing *"},
+		{`\T1a*1* ||`, 5, "a count of at least 2"},
+		{`\T1a*03* ||`, 5, "a count of at least 2"},
+		{`\T1a*3* *|`, 8, "a | after the separator"},
+		{`\T1a|b*|`, 6, "a | after the separator"},
+		{`\T1\a||`, 4, `an escaped \, * or |`},
+		{`\T1a*99999999999999999999* ||`, 5, "a count of at least 2"},
+		{`\T1*5*||`, 4, "a non-empty token or separator before a count"},
+		{`\T1*9223372036854775807*||`, 4, "a non-empty token or separator before a count"},
+		{`\T1a*300000000* ||`, 5, "a run within the output limit of 268435456 bytes"},
+	}
+	for _, test := range tests {
+		t.Logf("UnpackTokens(%q) should fail at byte %d.", test.input, test.offset)
+		_, err := UnpackTokens(test.input)
+		var syntaxErr *SyntaxError
+		if assert.True(t, errors.As(err, &syntaxErr)) {
+			assert.Equal(t, test.offset, syntaxErr.Offset)
+			assert.Equal(t, test.expected, syntaxErr.Expected)
+		}
+	}
+}
+
+func TestSplitters(t *testing.T) {
+	t.Log("Whitespace should treat every run of Unicode white space as one separator.")
+	assert.Equal(t, [][]int{{0, 1}, {2, 6}, {7, 8}}, Whitespace(" a \t\u00a0b\n"))
+	assert.Nil(t, Whitespace("abc"))
+
+	t.Log("Delimiter() should find every instance of the separator, like strings.Split.")
+	assert.Equal(t, [][]int{{1, 3}, {3, 5}}, Delimiter("--")("a----b"))
+	assert.Nil(t, Delimiter("")("abc"))
+
+	t.Log("Regexp() should ignore empty matches.")
+	assert.Equal(t, [][]int{{1, 3}}, Regexp(regexp.MustCompile(`,*`))("a,,b"))
+}
@@ -0,0 +1,230 @@
+package stringManipulator
+
+import (
+	"encoding/binary"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Transform is a reversible step of a Pipeline. Decode(Encode(src)) must return src for every input.
+// Every Codec is a Transform, so codecs and the transforms below can be stacked in any order.
+type Transform interface {
+	Name() string                      // Name() returns the name shown in Pipeline.Name
+	Encode(src []byte) ([]byte, error) // Encode() applies the transform
+	Decode(src []byte) ([]byte, error) // Decode() reverses Encode
+}
+
+// Transforms provided by this package. They rearrange bytes without compressing them,
+// so that a Codec placed after them in a Pipeline finds longer runs.
+var (
+	BWT Transform = bwtTransform{}
+	MTF Transform = mtfTransform{}
+)
+
+// bwtBlockSize is the number of bytes sorted at once by BWT
+const bwtBlockSize = 1 << 18
+
+// bwtTransform is the Burrows-Wheeler transform over blocks of bwtBlockSize bytes.
+// Each block is written as its uvarint length, the uvarint row of the block among its sorted rotations
+// and the last byte of every sorted rotation.
+type bwtTransform struct{}
+
+func (bwtTransform) Name() string { return "bwt" }
+
+func (bwtTransform) Encode(src []byte) ([]byte, error) {
+	var varint [binary.MaxVarintLen64]byte
+	dst := make([]byte, 0, len(src)+len(src)/bwtBlockSize*2*binary.MaxVarintLen64+2*binary.MaxVarintLen64)
+	for len(src) > 0 {
+		block := src[:min(len(src), bwtBlockSize)]
+		src = src[len(block):]
+
+		rotations := sortRotations(block)
+		primary := 0
+		for row, start := range rotations {
+			if start == 0 {
+				primary = row
+			}
+		}
+		dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(len(block)))]...)
+		dst = append(dst, varint[:binary.PutUvarint(varint[:], uint64(primary))]...)
+		for _, start := range rotations {
+			dst = append(dst, block[(start+len(block)-1)%len(block)])
+		}
+	}
+	return dst, nil
+}
+
+func (bwtTransform) Decode(src []byte) ([]byte, error) {
+	dst := make([]byte, 0, len(src))
+	for len(src) > 0 {
+		n, k := binary.Uvarint(src)
+		if k <= 0 || n == 0 || n > bwtBlockSize {
+			return nil, fmt.Errorf("%w: BWT block length is invalid", ErrMalformed)
+		}
+		src = src[k:]
+		primary, k := binary.Uvarint(src)
+		if k <= 0 || primary >= n || uint64(len(src)-k) < n {
+			return nil, fmt.Errorf("%w: BWT block is truncated", ErrMalformed)
+		}
+		last := src[k : k+int(n)]
+		src = src[k+int(n):]
+		dst = append(dst, inverseBWT(last, int(primary))...)
+	}
+	return dst, nil
+}
+
+// sortRotations returns the start of every rotation of b in sorted order, using prefix doubling.
+// After the round comparing the first k bytes of each rotation, rank holds their order,
+// so the next round compares 2k bytes by the pair of ranks at i and i+k.
+func sortRotations(b []byte) []int {
+	n := len(b)
+	rotations := make([]int, n)
+	rank := make([]int, n)
+	next := make([]int, n)
+	for i := range b {
+		rotations[i] = i
+		rank[i] = int(b[i])
+	}
+
+	for k := 1; ; k *= 2 {
+		less := func(i, j int) bool {
+			if rank[i] != rank[j] {
+				return rank[i] < rank[j]
+			}
+			return rank[(i+k)%n] < rank[(j+k)%n]
+		}
+		sort.Slice(rotations, func(a, b int) bool { return less(rotations[a], rotations[b]) })
+
+		next[rotations[0]] = 0
+		for r := 1; r < n; r++ {
+			next[rotations[r]] = next[rotations[r-1]]
+			if less(rotations[r-1], rotations[r]) {
+				next[rotations[r]]++
+			}
+		}
+		rank, next = next, rank
+
+		// Rotations still tied after comparing n bytes are equal, as in a periodic block
+		if rank[rotations[n-1]] == n-1 || k >= n {
+			return rotations
+		}
+	}
+}
+
+// inverseBWT rebuilds a block from the last byte of its sorted rotations and the row of the block itself
+func inverseBWT(last []byte, primary int) []byte {
+	var first [256]int
+	for _, c := range last {
+		first[c]++
+	}
+	sum := 0
+	for c, count := range first {
+		first[c] = sum
+		sum += count
+	}
+
+	// lf[row] is the row of the rotation starting one byte earlier
+	lf := make([]int, len(last))
+	var seen [256]int
+	for row, c := range last {
+		lf[row] = first[c] + seen[c]
+		seen[c]++
+	}
+
+	dst := make([]byte, len(last))
+	for i, row := len(last)-1, primary; i >= 0; i-- {
+		dst[i] = last[row]
+		row = lf[row]
+	}
+	return dst
+}
+
+// mtfTransform replaces every byte with its position in a list of the 256 byte values,
+// then moves that byte to the front of the list. Recently repeated bytes become small numbers, and runs become zeros.
+type mtfTransform struct{}
+
+func (mtfTransform) Name() string { return "mtf" }
+
+func (mtfTransform) Encode(src []byte) ([]byte, error) {
+	order := mtfOrder()
+	dst := make([]byte, len(src))
+	for i, c := range src {
+		j := 0
+		for order[j] != c {
+			j++
+		}
+		dst[i] = byte(j)
+		copy(order[1:j+1], order[:j])
+		order[0] = c
+	}
+	return dst, nil
+}
+
+func (mtfTransform) Decode(src []byte) ([]byte, error) {
+	order := mtfOrder()
+	dst := make([]byte, len(src))
+	for i, b := range src {
+		j := int(b)
+		c := order[j]
+		dst[i] = c
+		copy(order[1:j+1], order[:j])
+		order[0] = c
+	}
+	return dst, nil
+}
+
+// mtfOrder returns the initial move-to-front list
+func mtfOrder() [256]byte {
+	var order [256]byte
+	for i := range order {
+		order[i] = byte(i)
+	}
+	return order
+}
+
+// Pipeline chains transforms and codecs, e.g. BWT, MTF, RLE and Huffman for a bzip2-style stack.
+// Encode runs the stages in order and Decode runs them in reverse. A Pipeline is itself a Transform.
+type Pipeline struct {
+	Stages []Transform
+}
+
+// Name returns the names of the stages joined by "+"
+func (p Pipeline) Name() string {
+	names := make([]string, len(p.Stages))
+	for i, s := range p.Stages {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+// Encode passes src through every stage in order
+func (p Pipeline) Encode(src []byte) ([]byte, error) {
+	for _, s := range p.Stages {
+		var err error
+		if src, err = s.Encode(src); err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Name(), err)
+		}
+	}
+	return src, nil
+}
+
+// Decode passes src through every stage in reverse order
+func (p Pipeline) Decode(src []byte) ([]byte, error) {
+	for i := len(p.Stages) - 1; i >= 0; i-- {
+		var err error
+		if src, err = p.Stages[i].Decode(src); err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Stages[i].Name(), err)
+		}
+	}
+	return src, nil
+}
+
+// Ratio returns the size of src encoded by the pipeline divided by the size of src, 0 for empty input
+func (p Pipeline) Ratio(src []byte) (float64, error) {
+	dst, err := p.Encode(src)
+	if err != nil || len(src) == 0 {
+		return 0, err
+	}
+	return float64(len(dst)) / float64(len(src)), nil
+}
@@ -0,0 +1,74 @@
+package stringManipulator
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransformsRoundTrip(t *testing.T) {
+	inputs := append(codecInputs, []byte("abababab"), []byte("banana"), bytes.Repeat([]byte("x"), bwtBlockSize+10), randomBytes(bwtBlockSize+1))
+	for _, tr := range []Transform{BWT, MTF} {
+		for _, input := range inputs {
+			t.Logf("%s: Decode(Encode()) should return the %d byte input.", tr.Name(), len(input))
+			encoded, err := tr.Encode(input)
+			assert.NoError(t, err)
+			result, err := tr.Decode(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, input, result)
+		}
+	}
+}
+
+func TestBWT(t *testing.T) {
+	t.Log("BWT should write the row of the block and the last column of its sorted rotations.")
+	encoded, err := BWT.Encode([]byte("banana"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("\x06\x03nnbaaa"), encoded)
+
+	encoded, err = BWT.Encode(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, encoded)
+
+	t.Log("BWT should reject blocks that are truncated or name a row out of range.")
+	for _, input := range [][]byte{{0x06, 0x06, 'n', 'n', 'b', 'a', 'a', 'a'}, {0x06, 0x03, 'n'}, {0x00}, {0x80}} {
+		_, err := BWT.Decode(input)
+		assert.True(t, errors.Is(err, ErrMalformed))
+	}
+}
+
+func TestMTF(t *testing.T) {
+	t.Log("MTF should turn repeated bytes into zeros.")
+	encoded, err := MTF.Encode([]byte("aaabbba"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{'a', 0, 0, 'b', 0, 0, 1}, encoded)
+}
+
+func TestPipeline(t *testing.T) {
+	p := Pipeline{Stages: []Transform{BWT, MTF, RLE, Huffman}}
+	assert.Equal(t, "bwt+mtf+rle+huffman", p.Name())
+	for _, input := range codecInputs {
+		t.Logf("%s: Decode(Encode()) should return the %d byte input.", p.Name(), len(input))
+		encoded, err := p.Encode(input)
+		assert.NoError(t, err)
+		result, err := p.Decode(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, input, result)
+	}
+
+	t.Log("BWT and MTF ahead of RLE should compress natural text better than RLE alone.")
+	text := []byte(strings.Repeat("the quick brown fox jumps over the lazy dog. ", 50))
+	plain, err := Pipeline{Stages: []Transform{RLE}}.Ratio(text)
+	assert.NoError(t, err)
+	stacked, err := Pipeline{Stages: []Transform{BWT, MTF, RLE}}.Ratio(text)
+	assert.NoError(t, err)
+	assert.True(t, stacked < plain/2, "bwt+mtf+rle ratio %.3f, rle ratio %.3f", stacked, plain)
+
+	t.Log("Pipeline.Decode() should name the stage that failed.")
+	_, err = p.Decode([]byte{0xff})
+	assert.True(t, errors.Is(err, ErrMalformed))
+	assert.Contains(t, err.Error(), "huffman: ")
+}
@@ -2,13 +2,31 @@ package models
 
 import (
 	"fmt"
+	"sync"
 
 	customErrors "github.com/kindaqt/assignment2/errors"
 )
 
-// Cache holds data in memory
+// cacheShards is the number of shards of a Cache. It is a power of two, so a hash picks a shard with a mask.
+const cacheShards = 32
+
+// FNV-1a parameters used to hash keys to shards
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+// Cache holds data in memory. It is safe for concurrent use.
+// Keys are spread over shards, each with its own lock, so goroutines using different keys rarely wait for each other.
+// The zero value is an empty cache ready to use.
 type Cache struct {
-	Values map[string][]byte
+	shards [cacheShards]cacheShard
+}
+
+// cacheShard holds the keys hashed to it
+type cacheShard struct {
+	sync.RWMutex
+	values map[string][]byte
 }
 
 // NewCachePersistence returns a Persistence interface
@@ -18,26 +36,40 @@ func NewCachePersistence() Persistence {
 
 // NewCache returns an instance of CacheInterface which has all the Persistence interface functionality plus additional functions by way of interface composition
 func NewCache() CacheInterface {
-	values := make(map[string][]byte)
-	return &Cache{
-		Values: values,
+	return &Cache{}
+}
+
+// shard returns the shard holding key, chosen by the FNV-1a hash of the key
+func (p *Cache) shard(key string) *cacheShard {
+	h := uint32(fnvOffset32)
+	for i := 0; i < len(key); i++ {
+		h ^= uint32(key[i])
+		h *= fnvPrime32
 	}
+	return &p.shards[h&(cacheShards-1)]
 }
 
 // Put updates or replaces resources in the repository based on the existence of said resource
 func (p *Cache) Put(key string, value []byte) error {
-	// Store Values
-	p.Values[key] = value
+	s := p.shard(key)
+	s.Lock()
+	if s.values == nil {
+		s.values = make(map[string][]byte)
+	}
+	s.values[key] = value
+	s.Unlock()
 
 	return nil
 }
 
 // Get retrieves a resource based on the key
 func (p *Cache) Get(key string) ([]byte, error) {
-	// Get Values
-	b, ok := p.Values[key]
+	s := p.shard(key)
+	s.RLock()
+	b, ok := s.values[key]
+	s.RUnlock()
 	if !ok {
-		return nil, customErrors.TemporaryError{fmt.Sprintf("Error while getting %v", key)}
+		return nil, customErrors.TemporaryError{Message: fmt.Sprintf("Error while getting %v", key)}
 	}
 
 	return b, nil
@@ -45,5 +77,20 @@ func (p *Cache) Get(key string) ([]byte, error) {
 
 // Flush deletes a record from cache
 func (p *Cache) Flush(key string) {
-	delete(p.Values, key)
+	s := p.shard(key)
+	s.Lock()
+	delete(s.values, key)
+	s.Unlock()
+}
+
+// Len returns the number of records in the cache
+func (p *Cache) Len() int {
+	n := 0
+	for i := range p.shards {
+		s := &p.shards[i]
+		s.RLock()
+		n += len(s.values)
+		s.RUnlock()
+	}
+	return n
 }
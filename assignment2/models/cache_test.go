@@ -1,6 +1,10 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/google/uuid"
@@ -26,8 +30,7 @@ func TestPersistenceCacheTestSuite(t *testing.T) {
 
 // Setup before each test
 func (s *PersistenceCacheTestSuite) SetupTest() {
-	values := make(map[string][]byte)
-	s.cache = &Cache{values}
+	s.cache = &Cache{}
 }
 
 ////////////////////////////
@@ -50,13 +53,16 @@ func (s *PersistenceCacheTestSuite) TestPut() {
 
 	err := s.cache.Put(key, value)
 	s.NoError(err, "Put() should not return an error.")
-	s.Equal(s.cache.Values[key], value)
+	b, err := s.cache.Get(key)
+	s.NoError(err)
+	s.Equal(value, b)
+	s.Equal(1, s.cache.Len())
 }
 
 func (s *PersistenceCacheTestSuite) TestGet() {
 	key := uuid.New().String()
 	value := []byte{0, 1, 2}
-	s.cache.Values[key] = value
+	s.NoError(s.cache.Put(key, value))
 	s.T().Logf("Get() should return %v of the the key (%v).", value, key)
 	b, err := s.cache.Get(key)
 	s.NoError(err, "Get should not return an error.")
@@ -73,8 +79,173 @@ func (s *PersistenceCacheTestSuite) TestGetError() {
 func (s *PersistenceCacheTestSuite) TestFlush() {
 	key := uuid.New().String()
 	value := []byte{0, 1, 2}
-	s.cache.Values[key] = value
+	s.NoError(s.cache.Put(key, value))
 	s.T().Logf("Flush() should remove the requested key from the cache.")
 	s.cache.Flush(key)
-	s.Nil(s.cache.Values[key], "")
+	_, err := s.cache.Get(key)
+	s.Error(err, "Get should return an error after Flush.")
+	s.Equal(0, s.cache.Len())
+
+	s.T().Logf("Flush() should ignore a key that is not in the cache.")
+	s.cache.Flush(key)
+}
+
+func (s *PersistenceCacheTestSuite) TestShards() {
+	s.T().Logf("Keys should be spread over every shard.")
+	for i := 0; i < 10*cacheShards; i++ {
+		s.NoError(s.cache.Put(strconv.Itoa(i), []byte{byte(i)}))
+	}
+	s.Equal(10*cacheShards, s.cache.Len())
+	for i := range s.cache.shards {
+		s.NotEmpty(s.cache.shards[i].values, "shard %d should hold keys", i)
+	}
+}
+
+// TestCacheParallel is meant for go test -race: it mixes Put, Get and Flush of shared and private keys from many goroutines
+func TestCacheParallel(t *testing.T) {
+	t.Log("Put(), Get() and Flush() should be safe for concurrent use.")
+	cache := NewCache()
+	const goroutines, ops = 64, 2000
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < ops; i++ {
+				shared := "shared-" + strconv.Itoa(i%16)
+				private := fmt.Sprintf("private-%d-%d", g, i%32)
+				switch i % 4 {
+				case 0:
+					assert.NoError(t, cache.Put(shared, []byte{byte(g)}))
+				case 1:
+					cache.Flush(shared)
+				default:
+					cache.Get(shared) // may or may not be present
+				}
+
+				value := []byte(strconv.Itoa(i))
+				assert.NoError(t, cache.Put(private, value))
+				b, err := cache.Get(private)
+				assert.NoError(t, err)
+				assert.Equal(t, value, b)
+			}
+		}(g)
+	}
+	wg.Wait()
+	assert.Equal(t, goroutines*32, cache.(*Cache).Len()-len(presentShared(cache)))
+}
+
+// presentShared returns the shared keys of TestCacheParallel still in cache
+func presentShared(cache CacheInterface) []string {
+	var keys []string
+	for i := 0; i < 16; i++ {
+		key := "shared-" + strconv.Itoa(i)
+		if _, err := cache.Get(key); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+//////////////////////////////
+// Benchmarks
+/////////////////////////////
+
+// benchmarkKeys is the number of distinct keys used by the benchmarks
+const benchmarkKeys = 1 << 12
+
+var benchmarkValue = []byte("a todo of moderate length")
+
+// store is the subset of Cache and sync.Map operations compared by the benchmarks
+type store interface {
+	Put(key string, value []byte)
+	Get(key string) bool
+}
+
+type cacheStore struct{ *Cache }
+
+func (c cacheStore) Put(key string, value []byte) { c.Cache.Put(key, value) }
+func (c cacheStore) Get(key string) bool {
+	_, err := c.Cache.Get(key)
+	return err == nil
+}
+
+type syncMapStore struct{ *sync.Map }
+
+func (m syncMapStore) Put(key string, value []byte) { m.Store(key, value) }
+func (m syncMapStore) Get(key string) bool {
+	_, ok := m.Load(key)
+	return ok
+}
+
+// benchmarkStores runs fn against a Cache and a sync.Map filled with benchmarkKeys keys
+func benchmarkStores(b *testing.B, fn func(b *testing.B, s store, keys []string)) {
+	keys := make([]string, benchmarkKeys)
+	for i := range keys {
+		keys[i] = "todo-" + strconv.Itoa(i)
+	}
+	stores := []struct {
+		name  string
+		store store
+	}{
+		{"Cache", cacheStore{&Cache{}}},
+		{"SyncMap", syncMapStore{&sync.Map{}}},
+	}
+	for _, s := range stores {
+		for _, key := range keys {
+			s.store.Put(key, benchmarkValue)
+		}
+		b.Run(s.name, func(b *testing.B) {
+			b.ReportAllocs()
+			fn(b, s.store, keys)
+		})
+	}
+}
+
+// startOffset returns a different first key for each goroutine of b.RunParallel, counted in goroutines,
+// so that they do not all walk the same keys, and hit the same shard, in lockstep
+func startOffset(goroutines *int64, keys []string) int {
+	return int(atomic.AddInt64(goroutines, 1)) * 7919 % len(keys)
+}
+
+// BenchmarkGet reads from many goroutines, the case sync.Map is designed for
+func BenchmarkGet(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s store, keys []string) {
+		var goroutines int64
+		b.RunParallel(func(pb *testing.PB) {
+			for i := startOffset(&goroutines, keys); pb.Next(); i++ {
+				s.Get(keys[i%len(keys)])
+			}
+		})
+	})
+}
+
+// BenchmarkPut overwrites keys from many goroutines
+func BenchmarkPut(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s store, keys []string) {
+		var goroutines int64
+		b.RunParallel(func(pb *testing.PB) {
+			for i := startOffset(&goroutines, keys); pb.Next(); i++ {
+				s.Put(keys[i%len(keys)], benchmarkValue)
+			}
+		})
+	})
+}
+
+// BenchmarkMixed does one Put for every three Gets, like a DAO reading through the cache and refreshing it after writes
+func BenchmarkMixed(b *testing.B) {
+	benchmarkStores(b, func(b *testing.B, s store, keys []string) {
+		var goroutines int64
+		b.RunParallel(func(pb *testing.PB) {
+			for i := startOffset(&goroutines, keys); pb.Next(); i++ {
+				key := keys[(i*7)%len(keys)]
+				if i%4 == 0 {
+					s.Put(key, benchmarkValue)
+				} else {
+					s.Get(key)
+				}
+			}
+		})
+	})
 }
@@ -0,0 +1,44 @@
+package models_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/jinzhu/gorm"
+	_ "github.com/jinzhu/gorm/dialects/postgres"
+	"github.com/kindaqt/assignment2/models"
+	"github.com/kindaqt/assignment2/models/persistencetest"
+)
+
+// psqlConnEnv names the environment variable holding the connection string of the database used by TestPsqlStoreContract
+const psqlConnEnv = "PSQL_TEST_CONN_STRING"
+
+func TestCacheContract(t *testing.T) {
+	persistencetest.Run(t, models.NewCachePersistence)
+}
+
+func TestPsqlStoreContract(t *testing.T) {
+	connString := os.Getenv(psqlConnEnv)
+	if connString == "" {
+		t.Skipf("Set %s to run the persistence tests against PostgreSQL.", psqlConnEnv)
+	}
+
+	// Create the table the store writes to
+	db, err := gorm.Open("postgres", connString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.AutoMigrate(&models.TodoGormModel{}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := models.NewPsqlStore("postgres", connString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NewPsqlStore returns a Persistence, which does not expose the store's Close
+	t.Cleanup(func() { store.(io.Closer).Close() })
+	persistencetest.Run(t, func() models.Persistence { return store })
+}
@@ -0,0 +1,166 @@
+// Package persistencetest is a conformance suite for implementations of models.Persistence.
+// Every implementation should pass it, so the TodoDAO can rely on the same behaviour whichever store it is given.
+package persistencetest
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/kindaqt/assignment2/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// largeValueSize is the size of the value stored by the large value test
+const largeValueSize = 4 << 20
+
+// Concurrency settings of the concurrent access test
+const (
+	goroutines      = 16
+	opsPerGoroutine = 200
+)
+
+// Run runs the contract tests as subtests of t. factory is called once per subtest and must return an empty or
+// freshly isolated store; keys are random UUIDs, so a store shared between runs, such as a database, is fine.
+func Run(t *testing.T, factory func() models.Persistence) {
+	tests := []struct {
+		name string
+		test func(*testing.T, models.Persistence)
+	}{
+		{"PutGet", testPutGet},
+		{"Overwrite", testOverwrite},
+		{"MissingKey", testMissingKey},
+		{"BinaryValues", testBinaryValues},
+		{"LargeValue", testLargeValue},
+		{"ConcurrentAccess", testConcurrentAccess},
+	}
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, factory())
+		})
+	}
+}
+
+func testPutGet(t *testing.T, p models.Persistence) {
+	key, other := uuid.New().String(), uuid.New().String()
+	t.Log("Get() should return the value given to Put() for each key.")
+	assert.NoError(t, p.Put(key, []byte("first value")))
+	assert.NoError(t, p.Put(other, []byte("other value")))
+
+	b, err := p.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first value"), b)
+	b, err = p.Get(other)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other value"), b)
+
+	t.Log("Get() should return the value as often as it is called.")
+	b, err = p.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("first value"), b)
+}
+
+func testOverwrite(t *testing.T, p models.Persistence) {
+	key := uuid.New().String()
+	t.Log("Put() should replace the value of an existing key.")
+	assert.NoError(t, p.Put(key, []byte("a longer original value")))
+	assert.NoError(t, p.Put(key, []byte("short")))
+	b, err := p.Get(key)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("short"), b)
+
+	t.Log("Put() should accept an empty value.")
+	assert.NoError(t, p.Put(key, []byte{}))
+	b, err = p.Get(key)
+	assert.NoError(t, err)
+	assert.Empty(t, b)
+}
+
+func testMissingKey(t *testing.T, p models.Persistence) {
+	t.Log("Get() should return an error and no value for a key that was never stored.")
+	b, err := p.Get(uuid.New().String())
+	assert.Error(t, err)
+	assert.Empty(t, b)
+
+	t.Log("Get() should not find a key that only differs in case or padding from a stored one.")
+	key := "Key-" + uuid.New().String()
+	assert.NoError(t, p.Put(key, []byte("value")))
+	for _, missing := range []string{"key-" + key[4:], key + " ", " " + key} {
+		_, err := p.Get(missing)
+		assert.Error(t, err, "Get(%q)", missing)
+	}
+}
+
+func testBinaryValues(t *testing.T, p models.Persistence) {
+	values := [][]byte{
+		{0},
+		{0, 0, 0},
+		{0xff, 0xfe, 0x00, 0x80},
+		[]byte("\xc3\x28 invalid UTF-8"),
+		[]byte("trailing NUL\x00"),
+		[]byte("quotes ' \" and backslash \\ and % _"),
+	}
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	values = append(values, all)
+
+	for _, value := range values {
+		key := uuid.New().String()
+		t.Logf("Get() should return the bytes %q unchanged.", value)
+		assert.NoError(t, p.Put(key, value))
+		b, err := p.Get(key)
+		assert.NoError(t, err)
+		assert.Equal(t, value, b)
+	}
+}
+
+func testLargeValue(t *testing.T, p models.Persistence) {
+	key := uuid.New().String()
+	value := make([]byte, largeValueSize)
+	rand.New(rand.NewSource(1)).Read(value)
+	t.Logf("Get() should return a value of %d bytes unchanged.", len(value))
+	assert.NoError(t, p.Put(key, value))
+	b, err := p.Get(key)
+	assert.NoError(t, err)
+	assert.True(t, len(b) == len(value) && string(b) == string(value), "the large value should round-trip")
+}
+
+func testConcurrentAccess(t *testing.T, p models.Persistence) {
+	t.Logf("Put() and Get() should be safe from %d goroutines at once.", goroutines)
+	shared := uuid.New().String()
+	assert.NoError(t, p.Put(shared, []byte("initial")))
+
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			prefix := uuid.New().String()
+			for i := 0; i < opsPerGoroutine; i++ {
+				key := fmt.Sprintf("%s-%d", prefix, i%10)
+				value := []byte(fmt.Sprintf("%d-%d", g, i))
+				assert.NoError(t, p.Put(key, value))
+				b, err := p.Get(key)
+				assert.NoError(t, err)
+				assert.Equal(t, value, b, "a goroutine's own key should hold its last write")
+
+				// Every goroutine also overwrites and reads a key they all share
+				if i%10 == 0 {
+					assert.NoError(t, p.Put(shared, value))
+				}
+				_, err = p.Get(shared)
+				assert.NoError(t, err)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	b, err := p.Get(shared)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^\d+-\d+$`, string(b), "the shared key should hold one of the writes")
+}
@@ -63,7 +63,7 @@ var testTodo Todo = Todo{
 	Message: "This is a test message",
 }
 
-var temporaryError = customErrors.TemporaryError{"some temporary error"}
+var temporaryError = customErrors.TemporaryError{Message: "some temporary error"}
 
 ////////////////////////////
 // Tests
@@ -140,7 +140,7 @@ func (s *TodoTestSuite) TestSaveCustomErrorCacheActive() {
 	// Mock Expectations
 	expectedByteArray, err := json.Marshal(testTodo)
 	s.NoError(err)
-	expectedError := customErrors.TemporaryError{"some temporary error"}
+	expectedError := customErrors.TemporaryError{Message: "some temporary error"}
 
 	s.mockPersistence.EXPECT().
 		Put(testTodo.ID, expectedByteArray).
@@ -225,7 +225,7 @@ func (s *TodoTestSuite) TestGetByIDCustomErrorCacheInactive() {
 	s.T().Log("GetByID() should return an error when Get() returns an error.")
 
 	// Mock Expectations: return nil, error
-	expectedError := customErrors.TemporaryError{"some temporary error"}
+	expectedError := customErrors.TemporaryError{Message: "some temporary error"}
 	s.mockPersistence.EXPECT().Get(testTodo.ID).Return(nil, expectedError).Times(3)
 
 	actualTodo, err := s.todoDAO.GetByID(testTodo.ID)
@@ -240,8 +240,8 @@ func (s *TodoTestSuite) TestGetByIDCustomErrorCacheActive() {
 	s.todoDAO.CacheActive = true
 
 	// Mock Expectations
-	s.mockCache.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{"some temporary error"}).Times(1)
-	s.mockPersistence.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{"some temporary error"}).Times(3)
+	s.mockCache.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{Message: "some temporary error"}).Times(1)
+	s.mockPersistence.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{Message: "some temporary error"}).Times(3)
 
 	actualTodo, err := s.todoDAO.GetByID(testTodo.ID)
 	s.EqualError(err, "some temporary error", "GetByID() should return an error when Get() returns an error.")
@@ -257,8 +257,8 @@ func (s *TodoTestSuite) TestGetByIDCustomTemporaryErrorCacheActive() {
 	// Mock Expectations
 	expectedByteArray, err := json.Marshal(testTodo)
 	s.NoError(err)
-	s.mockCache.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{"some temporary error"}).Return(expectedByteArray, nil)
-	s.mockPersistence.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{"some temporary error"}).Times(3)
+	s.mockCache.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{Message: "some temporary error"}).Return(expectedByteArray, nil)
+	s.mockPersistence.EXPECT().Get(testTodo.ID).Return(nil, customErrors.TemporaryError{Message: "some temporary error"}).Times(3)
 
 	actualTodo, err := s.todoDAO.GetByID(testTodo.ID)
 	s.Equal(Todo{}, actualTodo, "GetByID() should return an empty Todo.")